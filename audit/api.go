@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	// accept both RFC 3339 timestamps and durations relative to now (e.g. 24h)
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (t *Logger) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/audit", "Query the audit log (newest first)").
+		Tag("audit").
+		Query("since", "", "An RFC 3339 timestamp or a duration before now (e.g. 24h)").
+		Query("until", "", "An RFC 3339 timestamp or a duration before now").
+		Query("caller", "", "The token name").
+		Query("method", "", "e.g. POST").
+		Query("route", "", "The route pattern, e.g. /api/v1/opendexd/placeorder or /api/v1/logs/:service").
+		Query("limit", 0, "The maximum number of records (default 100)").
+		Returns([]Record{})
+}
+
+func (t *Logger) ConfigureRouter(r *gin.Engine) {
+	api := r.Group("/api")
+	{
+		api.GET("/v1/audit", func(c *gin.Context) {
+			since, err := parseTime(c.Query("since"))
+			if err != nil {
+				utils.JsonError(c, fmt.Sprintf("invalid since: %s", err), http.StatusBadRequest)
+				return
+			}
+			until, err := parseTime(c.Query("until"))
+			if err != nil {
+				utils.JsonError(c, fmt.Sprintf("invalid until: %s", err), http.StatusBadRequest)
+				return
+			}
+			limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+			if err != nil {
+				utils.JsonError(c, fmt.Sprintf("invalid limit: %s", err), http.StatusBadRequest)
+				return
+			}
+			records, err := t.Query(Filter{
+				Since:  since,
+				Until:  until,
+				Caller: c.Query("caller"),
+				Method: c.Query("method"),
+				Route:  c.Query("route"),
+				Limit:  limit,
+			})
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			c.JSON(http.StatusOK, records)
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxSize    = 10 * 1024 * 1024
+	DefaultMaxBackups = 5
+)
+
+// Record is one line of the audit log
+type Record struct {
+	Time     time.Time              `json:"time"`
+	Caller   string                 `json:"caller"`
+	TokenId  string                 `json:"tokenId,omitempty"`
+	Role     string                 `json:"role,omitempty"`
+	ClientIp string                 `json:"clientIp"`
+	Method   string                 `json:"method"`
+	Route    string                 `json:"route"`
+	Path     string                 `json:"path"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+	Status   int                    `json:"status"`
+	Outcome  string                 `json:"outcome"`
+	Error    string                 `json:"error,omitempty"`
+	Latency  int64                  `json:"latency"`
+}
+
+// Logger appends records as JSON lines to a file and rotates it to file.1, file.2... when it grows over maxSize
+type Logger struct {
+	file       string
+	maxSize    int64
+	maxBackups int
+
+	f     *os.File
+	size  int64
+	mutex *sync.Mutex
+
+	logger *logrus.Entry
+}
+
+func NewLogger() *Logger {
+	return &Logger{
+		maxSize:    DefaultMaxSize,
+		maxBackups: DefaultMaxBackups,
+		mutex:      &sync.Mutex{},
+		logger:     logrus.NewEntry(logrus.StandardLogger()).WithField("name", "audit"),
+	}
+}
+
+// Open starts appending to the file. Records logged before Open are dropped.
+func (t *Logger) Open(file string, maxSize int64, maxBackups int) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.file = file
+	t.maxSize = maxSize
+	t.maxBackups = maxBackups
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return t.open()
+}
+
+func (t *Logger) open() error {
+	f, err := os.OpenFile(t.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	t.f = f
+	t.size = info.Size()
+	return nil
+}
+
+func (t *Logger) backupName(i int) string {
+	return fmt.Sprintf("%s.%d", t.file, i)
+}
+
+func (t *Logger) rotate() error {
+	if err := t.f.Close(); err != nil {
+		return err
+	}
+	_ = os.Remove(t.backupName(t.maxBackups))
+	for i := t.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(t.backupName(i), t.backupName(i+1))
+	}
+	if err := os.Rename(t.file, t.backupName(1)); err != nil {
+		return err
+	}
+	return t.open()
+}
+
+// Log appends a record to the audit log
+func (t *Logger) Log(record Record) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		t.logger.Errorf("Failed to encode audit record: %s", err)
+		return
+	}
+	line = append(line, '\n')
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.f == nil {
+		t.logger.Errorf("Audit log is closed, dropped: %s", line)
+		return
+	}
+
+	if t.maxSize > 0 && t.size+int64(len(line)) > t.maxSize {
+		if err := t.rotate(); err != nil {
+			t.logger.Errorf("Failed to rotate audit log: %s", err)
+			return
+		}
+	}
+
+	n, err := t.f.Write(line)
+	t.size += int64(n)
+	if err != nil {
+		t.logger.Errorf("Failed to write audit record: %s", err)
+	}
+}
+
+// Filter selects records in Query. Zero values match everything.
+type Filter struct {
+	Since  time.Time
+	Until  time.Time
+	Caller string
+	// Method and Route (the route pattern, e.g. /api/v1/opendexd/placeorder) are matched separately
+	Method string
+	Route  string
+	Limit  int
+}
+
+func (t *Filter) match(r *Record) bool {
+	if !t.Since.IsZero() && r.Time.Before(t.Since) {
+		return false
+	}
+	if !t.Until.IsZero() && r.Time.After(t.Until) {
+		return false
+	}
+	if t.Caller != "" && r.Caller != t.Caller {
+		return false
+	}
+	if t.Method != "" && !strings.EqualFold(r.Method, t.Method) {
+		return false
+	}
+	if t.Route != "" && r.Route != t.Route {
+		return false
+	}
+	return true
+}
+
+func (t *Logger) readFile(file string, filter Filter) ([]Record, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var result []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.logger.Warnf("Skipped malformed audit record in %s: %s", file, err)
+			continue
+		}
+		if filter.match(&r) {
+			result = append(result, r)
+		}
+	}
+	return result, scanner.Err()
+}
+
+// Query returns matching records from the current and rotated files, newest first
+func (t *Logger) Query(filter Filter) ([]Record, error) {
+	// the files are read without the lock so that Log isn't blocked meanwhile. A rotation in between may skip or
+	// repeat some records.
+	t.mutex.Lock()
+	result := []Record{}
+	if t.file == "" {
+		t.mutex.Unlock()
+		return result, nil
+	}
+	files := []string{t.file}
+	for i := 1; i <= t.maxBackups; i++ {
+		files = append(files, t.backupName(i))
+	}
+	t.mutex.Unlock()
+
+	for _, file := range files {
+		records, err := t.readFile(file, filter)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(records) - 1; i >= 0; i-- {
+			result = append(result, records[i])
+			if filter.Limit > 0 && len(result) >= filter.Limit {
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
+func (t *Logger) Close() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.f == nil {
+		return nil
+	}
+	err := t.f.Close()
+	t.f = nil
+	return err
+}
@@ -0,0 +1,174 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	redacted = "[REDACTED]"
+	// maxBodySize limits the request bodies kept for the audit log
+	maxBodySize = 64 * 1024
+)
+
+var (
+	sensitiveKeys = []string{"password", "mnemonic", "secret", "token", "macaroon"}
+)
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sanitize redacts passwords, mnemonics and other secrets from request parameters
+func Sanitize(params map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if isSensitive(k) {
+			result[k] = redacted
+			continue
+		}
+		result[k] = sanitizeValue(v)
+	}
+	return result
+}
+
+func sanitizeValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		return Sanitize(value)
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = sanitizeValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// readCloser reads from Reader and closes the original request body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+func valuesToParams(values url.Values, params map[string]interface{}) {
+	for k, v := range values {
+		if len(v) == 1 {
+			params[k] = v[0]
+		} else {
+			params[k] = v
+		}
+	}
+}
+
+func collectParams(c *gin.Context, body []byte) map[string]interface{} {
+	params := map[string]interface{}{}
+	for _, p := range c.Params {
+		params[p.Key] = p.Value
+	}
+	valuesToParams(c.Request.URL.Query(), params)
+
+	if len(body) == 0 {
+		return params
+	}
+
+	contentType := c.ContentType()
+	if contentType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err == nil {
+			valuesToParams(values, params)
+		}
+	} else {
+		var m map[string]interface{}
+		if err := json.Unmarshal(body, &m); err == nil {
+			for k, v := range m {
+				params[k] = v
+			}
+		}
+	}
+	return params
+}
+
+// Middleware records every state-changing request. It goes before the auth middleware so that rejected requests are
+// recorded as well.
+func (t *Logger) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+
+		var body []byte
+		if c.Request.Body != nil {
+			// read at most maxBodySize+1 bytes and give the handlers the head followed by the rest of the body
+			original := c.Request.Body
+			head, err := ioutil.ReadAll(io.LimitReader(original, maxBodySize+1))
+			c.Request.Body = readCloser{io.MultiReader(bytes.NewReader(head), original), original}
+			if err == nil && len(head) <= maxBodySize {
+				body = head
+			}
+		}
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+
+		record := Record{
+			Time:     start.UTC(),
+			Caller:   "unknown",
+			ClientIp: c.ClientIP(),
+			Method:   c.Request.Method,
+			Route:    route,
+			Path:     c.Request.URL.Path,
+			Params:   Sanitize(collectParams(c, body)),
+			Status:   c.Writer.Status(),
+			Latency:  time.Since(start).Milliseconds(),
+		}
+
+		if identity := auth.GetIdentity(c); identity != nil {
+			record.Caller = identity.Name
+			record.TokenId = identity.TokenId
+			record.Role = string(identity.Role)
+		}
+
+		if record.Status < 400 {
+			record.Outcome = "success"
+		} else {
+			record.Outcome = "failure"
+			if err := c.Errors.Last(); err != nil {
+				record.Error = err.Error()
+			}
+		}
+
+		t.Log(record)
+	}
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"net/http"
+)
+
+type CreateTokenParams struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type CreateTokenResult struct {
+	Token
+	// Secret is the value to send in the "Authorization: Bearer <secret>" header
+	Secret string `json:"secret"`
+}
+
+func (t *Authenticator) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/tokens", "List the API tokens").
+		Tag("auth").Returns([]Token{})
+	spec.Route(http.MethodPost, "/api/v1/tokens", "Create an API token").
+		Tag("auth").Body(CreateTokenParams{}).ReturnsStatus(http.StatusCreated, CreateTokenResult{})
+	spec.Route(http.MethodDelete, "/api/v1/tokens/:id", "Revoke an API token").
+		Tag("auth").PathParam("id", "The token id").NoContent()
+}
+
+func (t *Authenticator) ConfigureRouter(r *gin.Engine) {
+	api := r.Group("/api")
+	{
+		api.GET("/v1/tokens", func(c *gin.Context) {
+			if !t.enabled {
+				utils.JsonError(c, "authentication is disabled", http.StatusNotFound)
+				return
+			}
+			c.JSON(http.StatusOK, t.store.List())
+		})
+		api.POST("/v1/tokens", func(c *gin.Context) {
+			if !t.enabled {
+				utils.JsonError(c, "authentication is disabled", http.StatusNotFound)
+				return
+			}
+			var params CreateTokenParams
+			err := c.BindJSON(&params)
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if params.Name == "" {
+				utils.JsonError(c, "missing name", http.StatusBadRequest)
+				return
+			}
+			role, err := ParseRole(params.Role)
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusBadRequest)
+				return
+			}
+			token, secret, err := t.store.Create(params.Name, role)
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			token.Hash = ""
+			c.JSON(http.StatusCreated, CreateTokenResult{Token: *token, Secret: secret})
+		})
+		api.DELETE("/v1/tokens/:id", func(c *gin.Context) {
+			if !t.enabled {
+				utils.JsonError(c, "authentication is disabled", http.StatusNotFound)
+				return
+			}
+			err := t.store.Revoke(c.Param("id"))
+			if err == errTokenNotFound {
+				utils.JsonError(c, err.Error(), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			c.Status(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package auth
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"github.com/sirupsen/logrus"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	identityKey = "auth.identity"
+)
+
+var (
+	errMissingToken = errors.New("missing token")
+	errInvalidToken = errors.New("invalid token")
+
+	errMissingClientCert = errors.New("client certificate required")
+)
+
+// Identity is the caller of a request
+type Identity struct {
+	Name    string `json:"name"`
+	TokenId string `json:"tokenId,omitempty"`
+	Role    Role   `json:"role"`
+}
+
+var (
+	// Anonymous is used when authentication is disabled
+	Anonymous = Identity{Name: "anonymous", Role: RoleAdmin}
+)
+
+// Policy maps routes to the scope required to access them
+type Policy struct {
+	routes map[string]Scope
+	mutex  *sync.RWMutex
+}
+
+func NewPolicy() *Policy {
+	return &Policy{
+		routes: map[string]Scope{},
+		mutex:  &sync.RWMutex{},
+	}
+}
+
+// DefaultPolicy requires ScopeRead for every GET route under /api and ScopeAdmin for the rest, with the trading
+// routes lowered to ScopeTrade and the sensitive GET routes raised to ScopeAdmin.
+func DefaultPolicy() *Policy {
+	p := NewPolicy()
+
+	p.Set(http.MethodPost, "/api/v1/opendexd/placeorder", ScopeTrade)
+	p.Set(http.MethodPost, "/api/v1/opendexd/removeorder", ScopeTrade)
+	p.Set(http.MethodPost, "/api/v1/opendexd/unlock", ScopeTrade)
+	p.Set(http.MethodGet, "/api/v1/boltz/deposit/:currency", ScopeTrade)
+
+	p.Set(http.MethodGet, "/api/v1/opendexd/getmnemonic", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/consoles", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/consoles/:id", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/tokens", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/audit", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/lockouts", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/clients", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/doctor", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/settings", ScopeAdmin)
+	p.Set(http.MethodGet, "/api/v1/settings/:service", ScopeAdmin)
+
+	// the passthrough requires ScopeAdmin itself for the methods added to the read-only allowlist
+	p.Set(http.MethodPost, "/api/v1/bitcoind/jsonrpc", ScopeRead)
+	p.Set(http.MethodPost, "/api/v1/litecoind/jsonrpc", ScopeRead)
+	p.Set(http.MethodPost, "/api/v1/geth/jsonrpc", ScopeRead)
+
+	// the Socket.IO server hosts the web console
+	p.Set(http.MethodGet, "/socket.io/", ScopeAdmin)
+	p.Set("WS", "/socket.io/", ScopeAdmin)
+
+	// clients fetch the fingerprint to pin the certificate
+	p.Set(http.MethodGet, "/api/v1/tls", ScopePublic)
+
+	// the API documentation contains no secrets
+	p.Set(http.MethodGet, "/api/openapi.json", ScopePublic)
+	p.Set(http.MethodGet, "/api/docs", ScopePublic)
+
+	// the launcher attaches from the internal Docker network and receives the backup, provider and recreate requests
+	p.Set(http.MethodGet, "/launcher", ScopeInternal)
+	p.Set("WS", "/launcher", ScopeInternal)
+
+	return p
+}
+
+// ParseNetworks parses CIDRs like 172.18.0.0/16
+func ParseNetworks(cidrs []string) ([]*net.IPNet, error) {
+	var result []*net.IPNet
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func routeKey(method string, path string) string {
+	return fmt.Sprintf("%s %s", method, path)
+}
+
+func (t *Policy) Set(method string, path string, scope Scope) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.routes[routeKey(method, path)] = scope
+}
+
+// ScopeOf returns the scope required by a route. The path should be the route pattern (e.g. /api/v1/logs/:service).
+func (t *Policy) ScopeOf(method string, path string) Scope {
+	t.mutex.RLock()
+	scope, ok := t.routes[routeKey(method, path)]
+	t.mutex.RUnlock()
+	if ok {
+		return scope
+	}
+	if !strings.HasPrefix(path, "/api") {
+		// static files of the web UI
+		return ScopePublic
+	}
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions {
+		return ScopeRead
+	}
+	return ScopeAdmin
+}
+
+type Authenticator struct {
+	store       *TokenStore
+	policy      *Policy
+	enabled     bool
+	clientCerts bool
+	checkCert   func(cert *x509.Certificate) error
+	// internalNetworks are trusted for the ScopeInternal routes besides the loopback
+	internalNetworks []*net.IPNet
+	logger           *logrus.Entry
+}
+
+func NewAuthenticator(policy *Policy) *Authenticator {
+	return &Authenticator{
+		store:   nil,
+		policy:  policy,
+		enabled: false,
+		logger:  logrus.NewEntry(logrus.StandardLogger()).WithField("name", "auth"),
+	}
+}
+
+// Enable turns on token authentication backed by the store
+func (t *Authenticator) Enable(store *TokenStore) {
+	t.store = store
+	t.enabled = true
+}
+
+// EnableClientCerts requires a verified TLS client certificate accepted by check on every route except the
+// ScopeInternal ones called from the trusted networks, and resolves callers from it. The role is taken from the
+// OrganizationalUnit of the certificate subject.
+func (t *Authenticator) EnableClientCerts(check func(cert *x509.Certificate) error) {
+	t.clientCerts = true
+	t.checkCert = check
+}
+
+// TrustNetworks lets the peers from the networks (e.g. the address of the launcher container) access the
+// ScopeInternal routes without a token. Only the loopback is trusted by default.
+func (t *Authenticator) TrustNetworks(networks []*net.IPNet) {
+	t.internalNetworks = networks
+}
+
+func (t *Authenticator) IsEnabled() bool {
+	return t.enabled
+}
+
+func (t *Authenticator) GetStore() *TokenStore {
+	return t.store
+}
+
+func (t *Authenticator) GetPolicy() *Policy {
+	return t.policy
+}
+
+// ExtractToken returns the token secret sent with the request
+func ExtractToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	// browsers can't set headers on WebSocket and EventSource connections
+	return r.URL.Query().Get("token")
+}
+
+func certIdentity(cert *x509.Certificate) (*Identity, error) {
+	if len(cert.Subject.OrganizationalUnit) == 0 {
+		return nil, errors.New("client certificate has no role")
+	}
+	role, err := ParseRole(cert.Subject.OrganizationalUnit[0])
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{Name: cert.Subject.CommonName, TokenId: "cert:" + cert.SerialNumber.Text(16), Role: role}, nil
+}
+
+// Authenticate resolves the caller of the request
+func (t *Authenticator) Authenticate(r *http.Request) (*Identity, error) {
+	var chains [][]*x509.Certificate
+	if r.TLS != nil {
+		chains = r.TLS.VerifiedChains
+	}
+	return t.Resolve(ExtractToken(r), chains)
+}
+
+// Resolve resolves the caller from a token secret or the verified chains of a TLS client certificate, which take
+// precedence. It is used by the transports which don't go through net/http, like the gRPC gateway.
+func (t *Authenticator) Resolve(secret string, verifiedChains [][]*x509.Certificate) (*Identity, error) {
+	if t.clientCerts && len(verifiedChains) > 0 {
+		return certIdentity(verifiedChains[0][0])
+	}
+	if !t.enabled {
+		identity := Anonymous
+		return &identity, nil
+	}
+	if secret == "" {
+		return nil, errMissingToken
+	}
+	token, err := t.store.Lookup(secret)
+	if err != nil {
+		return nil, errInvalidToken
+	}
+	return &Identity{Name: token.Name, TokenId: token.Id, Role: token.Role}, nil
+}
+
+// isInternal tells if the peer of the request is on the loopback or one of the trusted networks. The forwarded
+// headers are ignored.
+func (t *Authenticator) isInternal(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	if ip.IsLoopback() {
+		return true
+	}
+	for _, n := range t.internalNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyClientCert checks the certificate of every request since a certificate may be revoked while its connection
+// is kept alive
+func (t *Authenticator) verifyClientCert(r *http.Request) error {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return errMissingClientCert
+	}
+	if t.checkCert != nil {
+		return t.checkCert(r.TLS.VerifiedChains[0][0])
+	}
+	return nil
+}
+
+// Middleware enforces the policy on every route of the engine. It should be registered before any route.
+func (t *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		scope := t.policy.ScopeOf(c.Request.Method, path)
+		if scope == ScopeInternal {
+			if t.isInternal(c.Request) {
+				c.Next()
+				return
+			}
+			scope = ScopeAdmin
+		}
+
+		if t.clientCerts {
+			if err := t.verifyClientCert(c.Request); err != nil {
+				utils.JsonError(c, err.Error(), http.StatusUnauthorized)
+				c.Abort()
+				return
+			}
+		}
+
+		identity, err := t.Authenticate(c.Request)
+		if err != nil {
+			if scope == ScopePublic {
+				c.Next()
+				return
+			}
+			utils.JsonError(c, err.Error(), http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+
+		if !identity.Role.Allows(scope) {
+			t.logger.Debugf("Denied %s %s for %s (role=%s, scope=%s)", c.Request.Method, path, identity.Name, identity.Role, scope)
+			utils.JsonError(c, fmt.Sprintf("permission denied: %s scope required", scope), http.StatusForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// GetIdentity returns the caller resolved by the middleware
+func GetIdentity(c *gin.Context) *Identity {
+	value, ok := c.Get(identityKey)
+	if !ok {
+		return nil
+	}
+	return value.(*Identity)
+}
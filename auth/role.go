@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"fmt"
+)
+
+// Scope is a permission required by a route.
+type Scope string
+
+const (
+	// ScopePublic routes can be accessed without a token
+	ScopePublic Scope = ""
+	// ScopeRead covers all read-only routes (status, logs, balances, order book...)
+	ScopeRead Scope = "read"
+	// ScopeTrade covers placing/removing orders and unlocking the node
+	ScopeTrade Scope = "trade"
+	// ScopeInternal routes can be accessed without a token from the loopback and the networks of
+	// Authenticator.TrustNetworks and require ScopeAdmin from anywhere else
+	ScopeInternal Scope = "internal"
+	// ScopeAdmin covers withdrawals, mnemonic access, changepass, the console and token management
+	ScopeAdmin Scope = "admin"
+)
+
+// Role is assigned to a token and grants a set of scopes.
+type Role string
+
+const (
+	RoleReadonly Role = "readonly"
+	RoleTrader   Role = "trader"
+	RoleAdmin    Role = "admin"
+)
+
+var (
+	roleScopes = map[Role][]Scope{
+		RoleReadonly: {ScopeRead},
+		RoleTrader:   {ScopeRead, ScopeTrade},
+		RoleAdmin:    {ScopeRead, ScopeTrade, ScopeAdmin},
+	}
+)
+
+func ParseRole(value string) (Role, error) {
+	role := Role(value)
+	if _, ok := roleScopes[role]; !ok {
+		return "", fmt.Errorf("invalid role: %s", value)
+	}
+	return role, nil
+}
+
+// Allows checks if the role has been granted the scope
+func (t Role) Allows(scope Scope) bool {
+	if scope == ScopePublic {
+		return true
+	}
+	for _, s := range roleScopes[t] {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+var (
+	errTokenNotFound = errors.New("token not found")
+)
+
+type Token struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      Role      `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Hash is the hex encoded SHA-256 of the token secret. The secret itself is only returned once on creation.
+	Hash string `json:"hash,omitempty"`
+}
+
+type TokenStore struct {
+	file   string
+	tokens []Token
+	mutex  *sync.RWMutex
+}
+
+func NewTokenStore(file string) (*TokenStore, error) {
+	s := &TokenStore{
+		file:   file,
+		tokens: []Token{},
+		mutex:  &sync.RWMutex{},
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func newSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (t *TokenStore) load() error {
+	data, err := ioutil.ReadFile(t.file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, &t.tokens)
+}
+
+func (t *TokenStore) save() error {
+	data, err := json.MarshalIndent(t.tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.file), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.file, data, 0600)
+}
+
+// Create generates a new token and returns it together with its secret
+func (t *TokenStore) Create(name string, role Role) (*Token, string, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	token := Token{
+		Id:        uuid.New().String(),
+		Name:      name,
+		Role:      role,
+		CreatedAt: time.Now().UTC(),
+		Hash:      hashSecret(secret),
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.tokens = append(t.tokens, token)
+	if err := t.save(); err != nil {
+		t.tokens = t.tokens[:len(t.tokens)-1]
+		return nil, "", err
+	}
+
+	return &token, secret, nil
+}
+
+func (t *TokenStore) Revoke(id string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i, token := range t.tokens {
+		if token.Id == id {
+			t.tokens = append(t.tokens[:i], t.tokens[i+1:]...)
+			return t.save()
+		}
+	}
+	return errTokenNotFound
+}
+
+// List returns all tokens without their hashes
+func (t *TokenStore) List() []Token {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	result := make([]Token, 0, len(t.tokens))
+	for _, token := range t.tokens {
+		token.Hash = ""
+		result = append(result, token)
+	}
+	return result
+}
+
+func (t *TokenStore) Len() int {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return len(t.tokens)
+}
+
+// Lookup finds the token matching the secret
+func (t *TokenStore) Lookup(secret string) (*Token, error) {
+	hash := []byte(hashSecret(secret))
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	for _, token := range t.tokens {
+		if subtle.ConstantTimeCompare(hash, []byte(token.Hash)) == 1 {
+			token.Hash = ""
+			return &token, nil
+		}
+	}
+	return nil, errTokenNotFound
+}
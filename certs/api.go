@@ -0,0 +1,21 @@
+package certs
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"net/http"
+)
+
+func (t *Manager) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/tls", "Get the fingerprint and names of the TLS certificate").
+		Tag("tls").Returns(Info{})
+}
+
+func (t *Manager) ConfigureRouter(r *gin.Engine) {
+	api := r.Group("/api")
+	{
+		api.GET("/v1/tls", func(c *gin.Context) {
+			c.JSON(http.StatusOK, t.GetInfo())
+		})
+	}
+}
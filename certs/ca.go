@@ -0,0 +1,261 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"software.sslmate.com/src/go-pkcs12"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultClientValidity = 2 * 365 * 24 * time.Hour
+	caValidity            = 10 * 365 * 24 * time.Hour
+)
+
+var (
+	errClientNotFound = errors.New("client certificate not found")
+	errRevoked        = errors.New("client certificate revoked")
+)
+
+// Client is a client certificate issued by the CA. The role is stored in the OrganizationalUnit of the subject.
+type Client struct {
+	Serial    string     `json:"serial"`
+	Name      string     `json:"name"`
+	Role      string     `json:"role"`
+	NotBefore time.Time  `json:"notBefore"`
+	NotAfter  time.Time  `json:"notAfter"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CA issues and revokes client certificates for mutual TLS
+type CA struct {
+	dir  string
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+
+	clients []Client
+	mutex   *sync.RWMutex
+
+	logger *logrus.Entry
+}
+
+// NewCA loads the CA from dir or creates a new one
+func NewCA(dir string) (*CA, error) {
+	ca := &CA{
+		dir:     dir,
+		clients: []Client{},
+		mutex:   &sync.RWMutex{},
+		logger:  logrus.NewEntry(logrus.StandardLogger()).WithField("name", "certs.ca"),
+	}
+	if err := ca.loadOrCreate(); err != nil {
+		return nil, err
+	}
+	if err := ca.loadClients(); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+func (t *CA) certFile() string {
+	return filepath.Join(t.dir, "ca.crt")
+}
+
+func (t *CA) keyFile() string {
+	return filepath.Join(t.dir, "ca.key")
+}
+
+func (t *CA) clientsFile() string {
+	return filepath.Join(t.dir, "clients.json")
+}
+
+func (t *CA) loadOrCreate() error {
+	pair, err := tls.LoadX509KeyPair(t.certFile(), t.keyFile())
+	if err == nil {
+		cert, err := x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return err
+		}
+		key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+		if !ok {
+			return fmt.Errorf("unsupported CA key type in %s", t.keyFile())
+		}
+		t.cert = cert
+		t.key = key
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	t.logger.Infof("Creating client CA in %s", t.dir)
+	key, err := newKey()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "OpenDEX proxy client CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := createCertificate(template, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := WriteKeyPair(t.certFile(), t.keyFile(), der, key); err != nil {
+		return err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+	t.cert = cert
+	t.key = key
+	return nil
+}
+
+func (t *CA) loadClients() error {
+	data, err := ioutil.ReadFile(t.clientsFile())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, &t.clients)
+}
+
+func (t *CA) saveClients() error {
+	data, err := json.MarshalIndent(t.clients, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(t.clientsFile(), data, 0600)
+}
+
+// Issue creates a client certificate and returns it as a PKCS#12 bundle protected by the password
+func (t *CA) Issue(name string, role string, validity time.Duration, password string) (*Client, []byte, error) {
+	if validity == 0 {
+		validity = DefaultClientValidity
+	}
+	key, err := newKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name, OrganizationalUnit: []string{role}},
+		NotBefore:   now.Add(-time.Hour),
+		NotAfter:    now.Add(validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := createCertificate(template, key, t.cert, t.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	bundle, err := pkcs12.Encode(rand.Reader, key, cert, []*x509.Certificate{t.cert}, password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client := Client{
+		Serial:    cert.SerialNumber.Text(16),
+		Name:      name,
+		Role:      role,
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.clients = append(t.clients, client)
+	if err := t.saveClients(); err != nil {
+		t.clients = t.clients[:len(t.clients)-1]
+		return nil, nil, err
+	}
+	t.logger.Infof("Issued client certificate %s for %s (%s)", client.Serial, name, role)
+
+	return &client, bundle, nil
+}
+
+func (t *CA) Revoke(serial string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i, client := range t.clients {
+		if client.Serial == serial {
+			if client.RevokedAt != nil {
+				return nil
+			}
+			now := time.Now().UTC()
+			t.clients[i].RevokedAt = &now
+			t.logger.Infof("Revoked client certificate %s of %s", serial, client.Name)
+			return t.saveClients()
+		}
+	}
+	return errClientNotFound
+}
+
+func (t *CA) List() []Client {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	result := make([]Client, len(t.clients))
+	copy(result, t.clients)
+	return result
+}
+
+// Check rejects revoked or unknown client certificates. The chain has already been verified by crypto/tls.
+func (t *CA) Check(cert *x509.Certificate) error {
+	serial := cert.SerialNumber.Text(16)
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	for _, client := range t.clients {
+		if client.Serial == serial {
+			if client.RevokedAt != nil {
+				return errRevoked
+			}
+			return nil
+		}
+	}
+	return errClientNotFound
+}
+
+// ServerTLSConfig verifies the client certificate presented by a connection and rejects revoked ones. Connections
+// without a certificate are accepted so that the launcher can attach; the other routes require the certificate in
+// the auth middleware.
+func (t *CA) ServerTLSConfig(base *tls.Config) *tls.Config {
+	pool := x509.NewCertPool()
+	pool.AddCert(t.cert)
+	config := base.Clone()
+	config.ClientCAs = pool
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return nil
+		}
+		if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+			return errClientNotFound
+		}
+		return t.Check(verifiedChains[0][0])
+	}
+	return config
+}
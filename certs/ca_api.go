@@ -0,0 +1,88 @@
+package certs
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"mime"
+	"net/http"
+	"time"
+)
+
+type IssueParams struct {
+	Name     string `json:"name"`
+	Role     string `json:"role"`
+	Password string `json:"password"`
+	// Validity is a duration like "8760h"
+	Validity string `json:"validity"`
+}
+
+func (t *CA) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/clients", "List the client certificates").
+		Tag("tls").Returns([]Client{})
+	spec.Route(http.MethodPost, "/api/v1/clients", "Issue a client certificate").
+		Tag("tls").Body(IssueParams{}).
+		Produces(http.StatusCreated, "application/x-pkcs12", nil, "The certificate and key encrypted with the password")
+	spec.Route(http.MethodDelete, "/api/v1/clients/:serial", "Revoke a client certificate").
+		Tag("tls").PathParam("serial", "The hex encoded serial number").NoContent()
+	spec.Route(http.MethodGet, "/api/v1/clients/ca.crt", "Download the client CA certificate").
+		Tag("tls").Produces(http.StatusOK, "application/x-pem-file", nil, "The PEM encoded certificate")
+}
+
+func (t *CA) ConfigureRouter(r *gin.Engine, validRole func(string) error) {
+	api := r.Group("/api")
+	{
+		api.GET("/v1/clients", func(c *gin.Context) {
+			c.JSON(http.StatusOK, t.List())
+		})
+		// issue a client certificate and download it as PKCS#12
+		api.POST("/v1/clients", func(c *gin.Context) {
+			var params IssueParams
+			err := c.BindJSON(&params)
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if params.Name == "" {
+				utils.JsonError(c, "missing name", http.StatusBadRequest)
+				return
+			}
+			if err := validRole(params.Role); err != nil {
+				utils.JsonError(c, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var validity time.Duration
+			if params.Validity != "" {
+				validity, err = time.ParseDuration(params.Validity)
+				if err != nil {
+					utils.JsonError(c, fmt.Sprintf("invalid validity: %s", err), http.StatusBadRequest)
+					return
+				}
+			}
+			client, bundle, err := t.Issue(params.Name, params.Role, validity, params.Password)
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": client.Name + ".p12"}))
+			c.Header("X-Client-Serial", client.Serial)
+			c.Data(http.StatusCreated, "application/x-pkcs12", bundle)
+		})
+		api.DELETE("/v1/clients/:serial", func(c *gin.Context) {
+			err := t.Revoke(c.Param("serial"))
+			if err == errClientNotFound {
+				utils.JsonError(c, err.Error(), http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			c.Status(http.StatusNoContent)
+		})
+		api.GET("/v1/clients/ca.crt", func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/x-pem-file", pemEncode("CERTIFICATE", t.cert.Raw))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SANs are the subject alternative names of a certificate
+type SANs struct {
+	DNSNames    []string
+	IPAddresses []net.IP
+}
+
+// ParseSANs splits hostnames (including .onion addresses) and IP addresses. localhost and the loopback addresses are
+// always included.
+func ParseSANs(hosts []string) SANs {
+	sans := SANs{
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			if !containsIP(sans.IPAddresses, ip) {
+				sans.IPAddresses = append(sans.IPAddresses, ip)
+			}
+		} else if host != "" && !containsString(sans.DNSNames, host) {
+			sans.DNSNames = append(sans.DNSNames, host)
+		}
+	}
+	return sans
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, item := range ips {
+		if item.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Covers checks if the certificate contains all the SANs
+func (t SANs) Covers(cert *x509.Certificate) bool {
+	for _, name := range t.DNSNames {
+		if !containsString(cert.DNSNames, name) {
+			return false
+		}
+	}
+	for _, ip := range t.IPAddresses {
+		if !containsIP(cert.IPAddresses, ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func newSerialNumber() (*big.Int, error) {
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	return rand.Int(rand.Reader, limit)
+}
+
+func newKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// createCertificate signs the template with the parent's key, or self-signs it if parent is nil
+func createCertificate(template *x509.Certificate, key *ecdsa.PrivateKey, parent *x509.Certificate, parentKey crypto.Signer) ([]byte, error) {
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+	return x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+}
+
+// GenerateSelfSigned creates a self-signed server certificate for the key or a new key if it is nil
+func GenerateSelfSigned(sans SANs, validity time.Duration, key *ecdsa.PrivateKey) ([]byte, *ecdsa.PrivateKey, error) {
+	if key == nil {
+		var err error
+		if key, err = newKey(); err != nil {
+			return nil, nil, err
+		}
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "localhost", Organization: []string{"OpenDEX proxy"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              sans.DNSNames,
+		IPAddresses:           sans.IPAddresses,
+	}
+	der, err := createCertificate(template, key, nil, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return der, key, nil
+}
+
+func pemEncode(blockType string, data []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data})
+}
+
+func writePem(file string, blockType string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	content := pemEncode(blockType, data)
+	// write to a temporary file first so that a crash never leaves a truncated file behind
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// LoadKey reads an EC private key PEM file written by WriteKeyPair
+func LoadKey(keyFile string) (*ecdsa.PrivateKey, error) {
+	content, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil || block.Type != "EC PRIVATE KEY" {
+		return nil, fmt.Errorf("no EC private key in %s", keyFile)
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// WriteKeyPair saves the certificate and the key as PEM files
+func WriteKeyPair(certFile string, keyFile string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	if err := writePem(keyFile, "EC PRIVATE KEY", keyDer, 0600); err != nil {
+		return err
+	}
+	return writePem(certFile, "CERTIFICATE", der, 0644)
+}
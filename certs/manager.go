@@ -0,0 +1,251 @@
+package certs
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"github.com/sirupsen/logrus"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultValidity    = 365 * 24 * time.Hour
+	DefaultRenewBefore = 30 * 24 * time.Hour
+	checkInterval      = time.Hour
+)
+
+type Options struct {
+	// CertFile and KeyFile are the server certificate files. They are generated unless UserProvided is set.
+	CertFile string
+	KeyFile  string
+	// UserProvided certificates are never regenerated but reloaded when the files change
+	UserProvided bool
+	// Hosts are extra hostnames, IP addresses or .onion addresses of the generated certificate
+	Hosts       []string
+	Validity    time.Duration
+	RenewBefore time.Duration
+}
+
+// Info describes the current certificate so that clients can pin it
+type Info struct {
+	// Fingerprint is the SHA-256 of the DER encoded certificate
+	Fingerprint string `json:"fingerprint"`
+	// SpkiPin is the base64 encoded SHA-256 of the public key which survives renewals with the same key
+	SpkiPin      string    `json:"spkiPin"`
+	Subject      string    `json:"subject"`
+	DNSNames     []string  `json:"dnsNames"`
+	IPAddresses  []string  `json:"ipAddresses"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	UserProvided bool      `json:"userProvided"`
+}
+
+// Manager serves the current certificate through GetCertificate, so that renewed certificates are picked up without
+// restarting the listener
+type Manager struct {
+	options Options
+	sans    SANs
+
+	cert    *tls.Certificate
+	leaf    *x509.Certificate
+	modTime time.Time
+	// keyModTime is the modification time of a user provided key file
+	keyModTime time.Time
+	mutex      *sync.RWMutex
+
+	logger *logrus.Entry
+}
+
+func NewManager(options Options) (*Manager, error) {
+	if options.Validity == 0 {
+		options.Validity = DefaultValidity
+	}
+	if options.RenewBefore == 0 {
+		options.RenewBefore = DefaultRenewBefore
+	}
+	m := &Manager{
+		options: options,
+		sans:    ParseSANs(options.Hosts),
+		mutex:   &sync.RWMutex{},
+		logger:  logrus.NewEntry(logrus.StandardLogger()).WithField("name", "certs"),
+	}
+	if err := m.refresh(); err != nil {
+		return nil, err
+	}
+	go m.watch()
+	return m, nil
+}
+
+func fileModTime(file string) (time.Time, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+func (t *Manager) load() error {
+	cert, err := tls.LoadX509KeyPair(t.options.CertFile, t.options.KeyFile)
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return err
+	}
+	modTime, err := fileModTime(t.options.CertFile)
+	if err != nil {
+		return err
+	}
+	keyModTime, err := fileModTime(t.options.KeyFile)
+	if err != nil {
+		return err
+	}
+	cert.Leaf = leaf
+
+	t.mutex.Lock()
+	t.cert = &cert
+	t.leaf = leaf
+	t.modTime = modTime
+	t.keyModTime = keyModTime
+	t.mutex.Unlock()
+
+	t.logger.Infof("Loaded TLS certificate %s (expires %s, fingerprint %s)", t.options.CertFile, leaf.NotAfter.Format(time.RFC3339), fingerprint(leaf))
+	return nil
+}
+
+func (t *Manager) generate() error {
+	t.logger.Infof("Generating TLS certificate for %s %v", strings.Join(t.sans.DNSNames, ", "), t.sans.IPAddresses)
+	// renew with the existing key so that the SPKI pin of the clients stays valid
+	key, err := LoadKey(t.options.KeyFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			t.logger.Warnf("Failed to load TLS key, generating a new one: %s", err)
+		}
+		key = nil
+	}
+	der, key, err := GenerateSelfSigned(t.sans, t.options.Validity, key)
+	if err != nil {
+		return err
+	}
+	if err := WriteKeyPair(t.options.CertFile, t.options.KeyFile, der, key); err != nil {
+		return err
+	}
+	return t.load()
+}
+
+// needsRenewal checks if a generated certificate is about to expire or lacks one of the configured SANs
+func (t *Manager) needsRenewal(leaf *x509.Certificate) bool {
+	if time.Now().Add(t.options.RenewBefore).After(leaf.NotAfter) {
+		return true
+	}
+	return !t.sans.Covers(leaf)
+}
+
+// refresh (re)loads the certificate from disk and renews generated certificates when needed
+func (t *Manager) refresh() error {
+	t.mutex.RLock()
+	leaf := t.leaf
+	modTime := t.modTime
+	keyModTime := t.keyModTime
+	t.mutex.RUnlock()
+
+	if t.options.UserProvided {
+		current, err := fileModTime(t.options.CertFile)
+		if err != nil {
+			return err
+		}
+		currentKey, err := fileModTime(t.options.KeyFile)
+		if err != nil {
+			return err
+		}
+		if leaf == nil || !current.Equal(modTime) || !currentKey.Equal(keyModTime) {
+			return t.load()
+		}
+		if time.Now().Add(t.options.RenewBefore).After(leaf.NotAfter) {
+			t.logger.Warnf("TLS certificate %s expires at %s", t.options.CertFile, leaf.NotAfter.Format(time.RFC3339))
+		}
+		return nil
+	}
+
+	if leaf == nil {
+		if err := t.load(); err != nil {
+			if !os.IsNotExist(err) {
+				t.logger.Warnf("Failed to load TLS certificate: %s", err)
+			}
+			return t.generate()
+		}
+		t.mutex.RLock()
+		leaf = t.leaf
+		t.mutex.RUnlock()
+	}
+
+	if t.needsRenewal(leaf) {
+		return t.generate()
+	}
+	return nil
+}
+
+func (t *Manager) watch() {
+	ticker := time.NewTicker(checkInterval)
+	for range ticker.C {
+		if err := t.refresh(); err != nil {
+			t.logger.Errorf("Failed to refresh TLS certificate: %s", err)
+		}
+	}
+}
+
+// GetCertificate implements tls.Config.GetCertificate
+func (t *Manager) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.cert, nil
+}
+
+func fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = strings.ToUpper(hex.EncodeToString([]byte{b}))
+	}
+	return strings.Join(parts, ":")
+}
+
+func spkiPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func (t *Manager) GetInfo() Info {
+	t.mutex.RLock()
+	leaf := t.leaf
+	t.mutex.RUnlock()
+
+	var ips []string
+	for _, ip := range leaf.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	return Info{
+		Fingerprint:  fingerprint(leaf),
+		SpkiPin:      spkiPin(leaf),
+		Subject:      leaf.Subject.String(),
+		DNSNames:     leaf.DNSNames,
+		IPAddresses:  ips,
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+		UserProvided: t.options.UserProvided,
+	}
+}
+
+// TLSConfig returns a server configuration using the managed certificate
+func (t *Manager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: t.GetCertificate,
+	}
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Doctor checks config.json, the TLS certificates, macaroons, RPC ports and Docker of the proxy
+func (t *Client) Doctor(ctx context.Context) (*DoctorReport, error) {
+	var report DoctorReport
+	if err := t.get(ctx, "/api/v1/doctor", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Settings returns the settings of the services in config.json
+func (t *Client) Settings(ctx context.Context) ([]ServiceSettings, error) {
+	var settings []ServiceSettings
+	err := t.get(ctx, "/api/v1/settings", nil, &settings)
+	return settings, err
+}
+
+func (t *Client) ServiceSettings(ctx context.Context, service string) (*ServiceSettings, error) {
+	var settings ServiceSettings
+	if err := t.get(ctx, "/api/v1/settings/"+url.PathEscape(service), nil, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// PreviewSettings validates a change and returns the differences without applying it
+func (t *Client) PreviewSettings(ctx context.Context, service string, change SettingsChange) (*SettingsPreview, error) {
+	var preview SettingsPreview
+	if err := t.post(ctx, "/api/v1/settings/"+url.PathEscape(service)+"/preview", change, &preview); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// ApplySettings writes a change to config.json and lets the launcher recreate the affected containers
+func (t *Client) ApplySettings(ctx context.Context, service string, change SettingsChange) (*SettingsPreview, error) {
+	var preview SettingsPreview
+	if err := t.do(ctx, http.MethodPut, "/api/v1/settings/"+url.PathEscape(service), nil, change, &preview); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+func (t *Client) Tokens(ctx context.Context) ([]Token, error) {
+	var tokens []Token
+	err := t.get(ctx, "/api/v1/tokens", nil, &tokens)
+	return tokens, err
+}
+
+// CreateToken returns the new token with its secret which cannot be retrieved again
+func (t *Client) CreateToken(ctx context.Context, params CreateTokenParams) (*CreateTokenResult, error) {
+	var result CreateTokenResult
+	if err := t.post(ctx, "/api/v1/tokens", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (t *Client) RevokeToken(ctx context.Context, id string) error {
+	return t.delete(ctx, "/api/v1/tokens/"+url.PathEscape(id))
+}
+
+// Audit queries the audit log (newest first). Zero fields of the filter are left to the defaults of the proxy.
+func (t *Client) Audit(ctx context.Context, filter AuditFilter) ([]AuditRecord, error) {
+	query := url.Values{}
+	if !filter.Since.IsZero() {
+		query.Set("since", filter.Since.Format(time.RFC3339))
+	}
+	if !filter.Until.IsZero() {
+		query.Set("until", filter.Until.Format(time.RFC3339))
+	}
+	if filter.Caller != "" {
+		query.Set("caller", filter.Caller)
+	}
+	if filter.Method != "" {
+		query.Set("method", filter.Method)
+	}
+	if filter.Route != "" {
+		query.Set("route", filter.Route)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var records []AuditRecord
+	err := t.get(ctx, "/api/v1/audit", query, &records)
+	return records, err
+}
+
+func (t *Client) Lockouts(ctx context.Context) ([]Lockout, error) {
+	var lockouts []Lockout
+	err := t.get(ctx, "/api/v1/lockouts", nil, &lockouts)
+	return lockouts, err
+}
+
+func (t *Client) ClearLockout(ctx context.Context, subject string) error {
+	return t.delete(ctx, "/api/v1/lockouts/"+url.PathEscape(subject))
+}
+
+func (t *Client) TlsInfo(ctx context.Context) (*TlsInfo, error) {
+	var info TlsInfo
+	if err := t.get(ctx, "/api/v1/tls", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (t *Client) ClientCertificates(ctx context.Context) ([]ClientCertificate, error) {
+	var clients []ClientCertificate
+	err := t.get(ctx, "/api/v1/clients", nil, &clients)
+	return clients, err
+}
+
+// IssueClientCertificate returns the certificate and key as PKCS#12 encrypted with params.Password
+func (t *Client) IssueClientCertificate(ctx context.Context, params IssueParams) ([]byte, error) {
+	return t.doRaw(ctx, http.MethodPost, "/api/v1/clients", nil, params)
+}
+
+func (t *Client) RevokeClientCertificate(ctx context.Context, serial string) error {
+	return t.delete(ctx, "/api/v1/clients/"+url.PathEscape(serial))
+}
+
+// ClientCA returns the PEM encoded client CA certificate
+func (t *Client) ClientCA(ctx context.Context) ([]byte, error) {
+	return t.doRaw(ctx, http.MethodGet, "/api/v1/clients/ca.crt", nil, nil)
+}
@@ -0,0 +1,215 @@
+// Package client is a Go client of the proxy REST API
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultRetryDelay = time.Second
+)
+
+// AuthHook is called before sending every request (including retries) to add the credentials
+type AuthHook func(req *http.Request) error
+
+// BearerToken authenticates the requests with an API token
+func BearerToken(token string) AuthHook {
+	return func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+type Options struct {
+	// HttpClient defaults to http.DefaultClient. Use a custom transport for TLS client certificates or pinning.
+	HttpClient *http.Client
+	Auth       AuthHook
+	// MaxRetries is the number of retries of a request answered with 503 Service Unavailable (e.g. while opendexd
+	// is starting). Negative disables retrying.
+	MaxRetries int
+	// RetryDelay is doubled after every retry unless the response has a Retry-After header
+	RetryDelay time.Duration
+}
+
+type Client struct {
+	baseUrl    string
+	httpClient *http.Client
+	auth       AuthHook
+	maxRetries int
+	retryDelay time.Duration
+}
+
+// Error is a non-2xx response of the proxy
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (t *Error) Error() string {
+	if t.Message == "" {
+		return fmt.Sprintf("%d %s", t.StatusCode, http.StatusText(t.StatusCode))
+	}
+	return fmt.Sprintf("%d %s: %s", t.StatusCode, http.StatusText(t.StatusCode), t.Message)
+}
+
+// IsStatus tells whether err is an Error with the status code
+func IsStatus(err error, code int) bool {
+	e, ok := err.(*Error)
+	return ok && e.StatusCode == code
+}
+
+// New creates a client of the proxy at baseUrl (e.g. https://localhost:8080)
+func New(baseUrl string, options Options) *Client {
+	httpClient := options.HttpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	maxRetries := options.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = DefaultMaxRetries
+	} else if maxRetries < 0 {
+		maxRetries = 0
+	}
+	retryDelay := options.RetryDelay
+	if retryDelay == 0 {
+		retryDelay = DefaultRetryDelay
+	}
+	return &Client{
+		baseUrl:    strings.TrimSuffix(baseUrl, "/"),
+		httpClient: httpClient,
+		auth:       options.Auth,
+		maxRetries: maxRetries,
+		retryDelay: retryDelay,
+	}
+}
+
+func retryAfter(resp *http.Response, delay time.Duration) time.Duration {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return delay
+}
+
+func readError(resp *http.Response) error {
+	data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.Message == "" {
+		body.Message = strings.TrimSpace(string(data))
+	}
+	return &Error{StatusCode: resp.StatusCode, Message: body.Message}
+}
+
+// send sends a request and returns the response if its status is 2xx. The caller closes the body.
+func (t *Client) send(ctx context.Context, method string, path string, query url.Values, contentType string, body []byte) (*http.Response, error) {
+	u := t.baseUrl + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	delay := t.retryDelay
+	for attempt := 0; ; attempt++ {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, u, reader)
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if t.auth != nil {
+			if err := t.auth(req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := t.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+		if resp.StatusCode != http.StatusServiceUnavailable || attempt >= t.maxRetries {
+			err := readError(resp)
+			_ = resp.Body.Close()
+			return nil, err
+		}
+		wait := retryAfter(resp, delay)
+		_ = resp.Body.Close()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// do sends params as the JSON body and decodes the JSON response into result. Protobuf messages are decoded with
+// jsonpb.
+func (t *Client) do(ctx context.Context, method string, path string, query url.Values, params interface{}, result interface{}) error {
+	data, err := t.doRaw(ctx, method, path, query, params)
+	if err != nil {
+		return err
+	}
+	return decode(bytes.NewReader(data), result)
+}
+
+// doRaw is like do but returns the response body as is
+func (t *Client) doRaw(ctx context.Context, method string, path string, query url.Values, params interface{}) ([]byte, error) {
+	var body []byte
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+	resp, err := t.send(ctx, method, path, query, "application/json", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+func decode(r io.Reader, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if msg, ok := result.(proto.Message); ok {
+		u := jsonpb.Unmarshaler{AllowUnknownFields: true}
+		return u.Unmarshal(r, msg)
+	}
+	return json.NewDecoder(r).Decode(result)
+}
+
+func (t *Client) get(ctx context.Context, path string, query url.Values, result interface{}) error {
+	return t.do(ctx, http.MethodGet, path, query, nil, result)
+}
+
+func (t *Client) post(ctx context.Context, path string, params interface{}, result interface{}) error {
+	return t.do(ctx, http.MethodPost, path, nil, params, result)
+}
+
+func (t *Client) delete(ctx context.Context, path string) error {
+	return t.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
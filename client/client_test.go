@@ -0,0 +1,369 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/certs"
+	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/service/core"
+	"github.com/opendexnetwork/opendex-docker-api/service/opendexd"
+	pb "github.com/opendexnetwork/opendex-docker-api/service/opendexd/opendexrpc"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeXud answers the opendexd calls of the tests. The methods which are not overridden panic.
+type fakeXud struct {
+	pb.XudServer
+}
+
+func (fakeXud) GetInfo(context.Context, *pb.GetInfoRequest) (*pb.GetInfoResponse, error) {
+	return &pb.GetInfoResponse{Version: "1.0.0", NumPeers: 3}, nil
+}
+
+func (fakeXud) GetBalance(_ context.Context, req *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	if req.Currency == "DOGE" {
+		return nil, status.Error(codes.NotFound, "currency DOGE not found")
+	}
+	return &pb.GetBalanceResponse{Balances: map[string]*pb.Balance{
+		req.Currency: {TotalBalance: 150, ChannelBalance: 100},
+	}}, nil
+}
+
+func (fakeXud) SubscribeOrders(req *pb.SubscribeOrdersRequest, stream pb.Xud_SubscribeOrdersServer) error {
+	if req.Existing {
+		order := &pb.Order{Id: "existing", PairId: "LTC/BTC"}
+		if err := stream.Send(&pb.OrderUpdate{OrderUpdate: &pb.OrderUpdate_Order{Order: order}}); err != nil {
+			return err
+		}
+	}
+	removal := &pb.OrderRemoval{OrderId: "removed", PairId: "LTC/BTC"}
+	if err := stream.Send(&pb.OrderUpdate{OrderUpdate: &pb.OrderUpdate_OrderRemoval{OrderRemoval: removal}}); err != nil {
+		return err
+	}
+	return status.Error(codes.Unavailable, "opendexd is shutting down")
+}
+
+func (fakeXud) SubscribeSwaps(req *pb.SubscribeSwapsRequest, stream pb.Xud_SubscribeSwapsServer) error {
+	if err := stream.Send(&pb.SwapSuccess{OrderId: "maker", Quantity: 100}); err != nil {
+		return err
+	}
+	if req.IncludeTaker {
+		if err := stream.Send(&pb.SwapSuccess{OrderId: "taker", Quantity: 200}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startXud serves fakeXud over TLS like opendexd does and returns the RPC config of the opendexd service
+func startXud(t *testing.T, dir string) config.RpcConfig {
+	certFile := filepath.Join(dir, "tls.cert")
+	keyFile := filepath.Join(dir, "tls.key")
+	der, key, err := certs.GenerateSelfSigned(certs.ParseSANs(nil), time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := certs.WriteKeyPair(certFile, keyFile, der, key); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.Creds(creds))
+	pb.RegisterXudServer(server, fakeXud{})
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return config.RpcConfig{
+		"host":    "127.0.0.1",
+		"port":    float64(lis.Addr().(*net.TCPAddr).Port),
+		"tlsCert": certFile,
+	}
+}
+
+// unavailable answers the first n requests with 503 Service Unavailable like a proxy whose backends are starting
+type unavailable struct {
+	mutex *sync.Mutex
+	n     int
+	seen  int
+}
+
+func (t *unavailable) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t.mutex.Lock()
+		t.seen++
+		reject := t.seen <= t.n
+		t.mutex.Unlock()
+		if reject {
+			c.Header("Retry-After", "0")
+			utils.JsonError(c, "opendexd is starting", http.StatusServiceUnavailable)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+type testProxy struct {
+	server      *httptest.Server
+	token       string
+	unavailable *unavailable
+}
+
+// newTestProxy serves the opendexd routes of the proxy backed by fakeXud with token authentication
+func newTestProxy(t *testing.T) *testProxy {
+	gin.SetMode(gin.TestMode)
+
+	dir, err := ioutil.TempDir("", "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	store, err := auth.NewTokenStore(filepath.Join(dir, "tokens.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, token, err := store.Create("test", auth.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := auth.NewAuthenticator(auth.DefaultPolicy())
+	authenticator.Enable(store)
+
+	// the routes under test don't need the Docker daemon
+	factory, err := core.NewClientFactory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := opendexd.New("opendexd", map[string]core.Service{}, "testnet_opendexd_1", factory.GetSharedInstance(),
+		startXud(t, dir), dir, filepath.Join(dir, ".password-unset"))
+	t.Cleanup(func() {
+		_ = svc.Close()
+	})
+	waitForXud(t, svc)
+
+	u := &unavailable{mutex: &sync.Mutex{}}
+	r := gin.New()
+	r.Use(u.Middleware())
+	r.Use(authenticator.Middleware())
+	svc.ConfigureRouter(r.Group("/api"))
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return &testProxy{server: server, token: token, unavailable: u}
+}
+
+// waitForXud waits until the service has connected to fakeXud
+func waitForXud(t *testing.T, svc *opendexd.Service) {
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		_, err := svc.GetInfo(context.Background())
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Failed to connect to the fake opendexd: %s", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func (t *testProxy) client(options Options) *Client {
+	if options.Auth == nil {
+		options.Auth = BearerToken(t.token)
+	}
+	return New(t.server.URL, options)
+}
+
+func TestTypedCalls(t *testing.T) {
+	proxy := newTestProxy(t)
+	c := proxy.client(Options{})
+	ctx := context.Background()
+
+	info, err := c.GetInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "1.0.0" || info.NumPeers != 3 {
+		t.Errorf("GetInfo returned %v", info)
+	}
+
+	balance, err := c.GetBalance(ctx, "BTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := balance.Balances["BTC"]; b == nil || b.TotalBalance != 150 || b.ChannelBalance != 100 {
+		t.Errorf("GetBalance returned %v", balance)
+	}
+
+	_, err = c.GetBalance(ctx, "DOGE")
+	if !IsStatus(err, http.StatusInternalServerError) {
+		t.Fatalf("GetBalance of an unknown currency returned %v", err)
+	}
+	if msg := err.(*Error).Message; msg != "rpc error: code = NotFound desc = currency DOGE not found" {
+		t.Errorf("GetBalance of an unknown currency returned the message %q", msg)
+	}
+}
+
+func TestRetryUnavailable(t *testing.T) {
+	proxy := newTestProxy(t)
+	proxy.unavailable.n = 2
+
+	c := proxy.client(Options{MaxRetries: 2, RetryDelay: time.Millisecond})
+	if _, err := c.GetInfo(context.Background()); err != nil {
+		t.Fatalf("GetInfo failed after 2 retries: %s", err)
+	}
+	if proxy.unavailable.seen != 3 {
+		t.Errorf("sent %d requests, expected 3", proxy.unavailable.seen)
+	}
+
+	proxy.unavailable.n = 10
+	proxy.unavailable.seen = 0
+	_, err := c.GetInfo(context.Background())
+	if !IsStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("GetInfo returned %v when the proxy stays unavailable", err)
+	}
+	if proxy.unavailable.seen != 3 {
+		t.Errorf("sent %d requests, expected 3", proxy.unavailable.seen)
+	}
+
+	c = proxy.client(Options{MaxRetries: -1})
+	proxy.unavailable.seen = 0
+	if _, err := c.GetInfo(context.Background()); !IsStatus(err, http.StatusServiceUnavailable) {
+		t.Fatalf("GetInfo returned %v without retries", err)
+	}
+	if proxy.unavailable.seen != 1 {
+		t.Errorf("sent %d requests without retries", proxy.unavailable.seen)
+	}
+}
+
+func TestAuthHook(t *testing.T) {
+	proxy := newTestProxy(t)
+	ctx := context.Background()
+
+	c := proxy.client(Options{Auth: func(req *http.Request) error { return nil }})
+	if _, err := c.GetInfo(ctx); !IsStatus(err, http.StatusUnauthorized) {
+		t.Fatalf("GetInfo without a token returned %v", err)
+	}
+
+	c = proxy.client(Options{Auth: BearerToken("invalid")})
+	if _, err := c.GetInfo(ctx); !IsStatus(err, http.StatusUnauthorized) {
+		t.Fatalf("GetInfo with an invalid token returned %v", err)
+	}
+
+	// the hook runs again for every retry
+	proxy.unavailable.n = 1
+	proxy.unavailable.seen = 0
+	calls := 0
+	c = proxy.client(Options{RetryDelay: time.Millisecond, Auth: func(req *http.Request) error {
+		calls++
+		return BearerToken(proxy.token)(req)
+	}})
+	if _, err := c.GetInfo(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Errorf("the hook was called %d times, expected 2", calls)
+	}
+
+	hookErr := errors.New("no token")
+	c = proxy.client(Options{Auth: func(req *http.Request) error { return hookErr }})
+	if _, err := c.GetInfo(ctx); err != hookErr {
+		t.Fatalf("GetInfo returned %v when the hook fails", err)
+	}
+}
+
+func TestSubscribeOrders(t *testing.T) {
+	proxy := newTestProxy(t)
+	c := proxy.client(Options{})
+
+	stream, err := c.SubscribeOrders(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	update, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order := update.GetOrder(); order == nil || order.Id != "existing" {
+		t.Errorf("received %v, expected the existing order", update)
+	}
+
+	update, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removal := update.GetOrderRemoval(); removal == nil || removal.OrderId != "removed" {
+		t.Errorf("received %v, expected the order removal", update)
+	}
+
+	// the gRPC error ends the stream with an error line
+	if _, err := stream.Recv(); err == nil || err == io.EOF {
+		t.Fatalf("Recv returned %v at the end of a failed stream", err)
+	} else if err.Error() != "rpc error: code = Unavailable desc = opendexd is shutting down" {
+		t.Errorf("Recv returned the error %q", err)
+	}
+}
+
+func TestSubscribeSwaps(t *testing.T) {
+	proxy := newTestProxy(t)
+	c := proxy.client(Options{})
+
+	stream, err := c.SubscribeSwaps(context.Background(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	for _, expected := range []string{"maker", "taker"} {
+		swap, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if swap.OrderId != expected {
+			t.Errorf("received the swap of %s, expected %s", swap.OrderId, expected)
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("Recv returned %v at the end of the stream", err)
+	}
+}
+
+func TestSubscribeUnauthorized(t *testing.T) {
+	proxy := newTestProxy(t)
+	c := proxy.client(Options{Auth: BearerToken("invalid")})
+
+	if _, err := c.SubscribeSwaps(context.Background(), false); !IsStatus(err, http.StatusUnauthorized) {
+		t.Fatalf("SubscribeSwaps with an invalid token returned %v", err)
+	}
+}
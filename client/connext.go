@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// ConnextChains returns the chain providers of connext and the chain expected for the network
+func (t *Client) ConnextChains(ctx context.Context) (*Chains, error) {
+	var result Chains
+	if err := t.get(ctx, "/api/v1/connext/chains", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ConnextConfig returns the signers of the vector node
+func (t *Client) ConnextConfig(ctx context.Context) ([]NodeConfig, error) {
+	var result []NodeConfig
+	err := t.get(ctx, "/api/v1/connext/config", nil, &result)
+	return result, err
+}
+
+func (t *Client) ConnextChannels(ctx context.Context) ([]ChannelState, error) {
+	var result []ChannelState
+	err := t.get(ctx, "/api/v1/connext/channels", nil, &result)
+	return result, err
+}
+
+func (t *Client) ConnextChannel(ctx context.Context, address string) (*ChannelState, error) {
+	var result ChannelState
+	if err := t.get(ctx, "/api/v1/connext/channels/"+url.PathEscape(address), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ConnextBalances returns the local and remote balances (in wei) of every asset of a channel
+func (t *Client) ConnextBalances(ctx context.Context, address string) ([]AssetBalance, error) {
+	var result []AssetBalance
+	err := t.get(ctx, "/api/v1/connext/channels/"+url.PathEscape(address)+"/balances", nil, &result)
+	return result, err
+}
+
+// ConnextTransfers returns the transfers of the node or of the channel of the filter
+func (t *Client) ConnextTransfers(ctx context.Context, filter TransferFilter) ([]Transfer, error) {
+	query := url.Values{}
+	if filter.ChannelAddress != "" {
+		query.Set("channelAddress", filter.ChannelAddress)
+	}
+	if filter.Active {
+		query.Set("active", "true")
+	}
+	var result []Transfer
+	err := t.get(ctx, "/api/v1/connext/transfers", query, &result)
+	return result, err
+}
+
+// ConnextDeposit credits the on-chain deposits of an asset sent to the channel address to the channel balance
+func (t *Client) ConnextDeposit(ctx context.Context, params DepositParams) (*DepositResult, error) {
+	var result DepositResult
+	if err := t.post(ctx, "/api/v1/connext/deposit", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (t *Client) ConnextWithdraw(ctx context.Context, params WithdrawParams) (*WithdrawResult, error) {
+	var result WithdrawResult
+	if err := t.post(ctx, "/api/v1/connext/withdraw", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
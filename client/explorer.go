@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// The explorer routes of bitcoind and litecoind answer 409 in the light and external modes
+
+func explorerPath(service string, route string) string {
+	return "/api/v1/" + url.PathEscape(service) + "/" + route
+}
+
+func (t *Client) BlockchainInfo(ctx context.Context, service string) (*BlockchainInfo, error) {
+	var result BlockchainInfo
+	if err := t.get(ctx, explorerPath(service, "blockchaininfo"), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (t *Client) NetworkInfo(ctx context.Context, service string) (*NetworkInfo, error) {
+	var result NetworkInfo
+	if err := t.get(ctx, explorerPath(service, "networkinfo"), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (t *Client) Peers(ctx context.Context, service string) ([]PeerInfo, error) {
+	var result []PeerInfo
+	err := t.get(ctx, explorerPath(service, "peers"), nil, &result)
+	return result, err
+}
+
+func (t *Client) Mempool(ctx context.Context, service string) (*MempoolInfo, error) {
+	var result MempoolInfo
+	if err := t.get(ctx, explorerPath(service, "mempool"), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RecentBlocks returns the headers of the last blocks, the best block first. A zero count uses the default of 10.
+func (t *Client) RecentBlocks(ctx context.Context, service string, count int) ([]BlockHeader, error) {
+	query := url.Values{}
+	if count > 0 {
+		query.Set("count", strconv.Itoa(count))
+	}
+	var result []BlockHeader
+	err := t.get(ctx, explorerPath(service, "blocks"), query, &result)
+	return result, err
+}
+
+// Block returns a block by its hash or height
+func (t *Client) Block(ctx context.Context, service string, block string) (*Block, error) {
+	var result Block
+	if err := t.get(ctx, explorerPath(service, "blocks/"+url.PathEscape(block)), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// BlockHeader returns a block header by its hash or height
+func (t *Client) BlockHeader(ctx context.Context, service string, block string) (*BlockHeader, error) {
+	var result BlockHeader
+	if err := t.get(ctx, explorerPath(service, "blocks/"+url.PathEscape(block)+"/header"), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Transaction returns a transaction. The blockHash is needed for the transactions of the chain when the node has no
+// -txindex.
+func (t *Client) Transaction(ctx context.Context, service string, txId string, blockHash string) (*RawTransaction, error) {
+	query := url.Values{}
+	if blockHash != "" {
+		query.Set("blockhash", blockHash)
+	}
+	var result RawTransaction
+	if err := t.get(ctx, explorerPath(service, "transactions/"+url.PathEscape(txId)), query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// EstimateFee estimates the fee rate for a confirmation within target blocks. Zero values use the defaults of the
+// proxy (6 blocks, CONSERVATIVE).
+func (t *Client) EstimateFee(ctx context.Context, service string, target int32, mode string) (*FeeEstimate, error) {
+	query := url.Values{}
+	if target > 0 {
+		query.Set("target", strconv.Itoa(int(target)))
+	}
+	if mode != "" {
+		query.Set("mode", mode)
+	}
+	var result FeeEstimate
+	if err := t.get(ctx, explorerPath(service, "estimatefee"), query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
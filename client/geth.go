@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// GethProviders returns the health of the light providers of connext, the best first
+func (t *Client) GethProviders(ctx context.Context) (*Providers, error) {
+	var result Providers
+	if err := t.get(ctx, "/api/v1/geth/providers", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SwitchProvider asks the launcher to recreate connext with a light provider, the recommended one if provider is
+// empty. It fails with 409 while another switch is in progress.
+func (t *Client) SwitchProvider(ctx context.Context, provider string) (*Providers, error) {
+	var result Providers
+	if err := t.post(ctx, "/api/v1/geth/providers/switch", SwitchProviderParams{Provider: provider}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GethBalances returns the ether and token balances of address, the connext signer if it is empty
+func (t *Client) GethBalances(ctx context.Context, address string) (*Balances, error) {
+	query := url.Values{}
+	if address != "" {
+		query.Set("address", address)
+	}
+	var result Balances
+	if err := t.get(ctx, "/api/v1/geth/balances", query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GethSyncing returns the sync state of the provider of the current mode of geth
+func (t *Client) GethSyncing(ctx context.Context) (*SyncState, error) {
+	var result SyncState
+	if err := t.get(ctx, "/api/v1/geth/syncing", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
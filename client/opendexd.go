@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	pb "github.com/opendexnetwork/opendex-docker-api/service/opendexd/opendexrpc"
+	"net/url"
+	"strconv"
+)
+
+func (t *Client) GetInfo(ctx context.Context) (*pb.GetInfoResponse, error) {
+	var resp pb.GetInfoResponse
+	if err := t.get(ctx, "/api/v1/opendexd/getinfo", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetBalance returns the balances of all currencies if currency is empty
+func (t *Client) GetBalance(ctx context.Context, currency string) (*pb.GetBalanceResponse, error) {
+	path := "/api/v1/opendexd/getbalance"
+	if currency != "" {
+		path += "/" + url.PathEscape(currency)
+	}
+	var resp pb.GetBalanceResponse
+	if err := t.get(ctx, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TradeHistory returns the completed trades. A zero limit returns all of them.
+func (t *Client) TradeHistory(ctx context.Context, limit uint32) (*pb.TradeHistoryResponse, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.FormatUint(uint64(limit), 10))
+	}
+	var resp pb.TradeHistoryResponse
+	if err := t.get(ctx, "/api/v1/opendexd/tradehistory", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// TradingLimits returns the trading limits of all currencies if currency is empty
+func (t *Client) TradingLimits(ctx context.Context, currency string) (*pb.TradingLimitsResponse, error) {
+	path := "/api/v1/opendexd/tradinglimits"
+	if currency != "" {
+		path += "/" + url.PathEscape(currency)
+	}
+	var resp pb.TradingLimitsResponse
+	if err := t.get(ctx, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) CreateNode(ctx context.Context, params CreateParams) (*pb.CreateNodeResponse, error) {
+	var resp pb.CreateNodeResponse
+	if err := t.post(ctx, "/api/v1/opendexd/create", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) RestoreNode(ctx context.Context, params RestoreParams) (*pb.RestoreNodeResponse, error) {
+	var resp pb.RestoreNodeResponse
+	if err := t.post(ctx, "/api/v1/opendexd/restore", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) Unlock(ctx context.Context, params UnlockParams) (*pb.UnlockNodeResponse, error) {
+	var resp pb.UnlockNodeResponse
+	if err := t.post(ctx, "/api/v1/opendexd/unlock", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) ChangePassword(ctx context.Context, params ChangepasswordParams) (*pb.ChangePasswordResponse, error) {
+	var resp pb.ChangePasswordResponse
+	if err := t.post(ctx, "/api/v1/opendexd/changepass", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) GetMnemonic(ctx context.Context) (*pb.GetMnemonicResponse, error) {
+	var resp pb.GetMnemonicResponse
+	if err := t.get(ctx, "/api/v1/opendexd/getmnemonic", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) ListPairs(ctx context.Context) (*pb.ListPairsResponse, error) {
+	var resp pb.ListPairsResponse
+	if err := t.get(ctx, "/api/v1/opendexd/listpairs", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) ListOrders(ctx context.Context, params ListOrdersParams) (*pb.ListOrdersResponse, error) {
+	query := url.Values{}
+	query.Set("pairId", params.PairId)
+	query.Set("owner", strconv.Itoa(int(params.Owner)))
+	query.Set("limit", strconv.FormatUint(uint64(params.Limit), 10))
+	query.Set("includeAliases", strconv.FormatBool(params.IncludeAliases))
+	var resp pb.ListOrdersResponse
+	if err := t.get(ctx, "/api/v1/opendexd/listorders", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) OrderBook(ctx context.Context, params OrderBookParams) (*pb.OrderBookResponse, error) {
+	query := url.Values{}
+	query.Set("pairId", params.PairId)
+	query.Set("precision", strconv.Itoa(int(params.Precision)))
+	query.Set("limit", strconv.FormatUint(uint64(params.Limit), 10))
+	var resp pb.OrderBookResponse
+	if err := t.get(ctx, "/api/v1/opendexd/orderbook", query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) PlaceOrder(ctx context.Context, params PlaceOrderParams) (*pb.PlaceOrderResponse, error) {
+	var resp pb.PlaceOrderResponse
+	if err := t.post(ctx, "/api/v1/opendexd/placeorder", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) RemoveOrder(ctx context.Context, params RemoveOrderParams) (*pb.RemoveOrderResponse, error) {
+	var resp pb.RemoveOrderResponse
+	if err := t.post(ctx, "/api/v1/opendexd/removeorder", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
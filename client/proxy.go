@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+func (t *Client) Version(ctx context.Context) (string, error) {
+	var version string
+	err := t.get(ctx, "/api/v1/version", nil, &version)
+	return version, err
+}
+
+func (t *Client) Services(ctx context.Context) ([]ServiceEntry, error) {
+	var services []ServiceEntry
+	err := t.get(ctx, "/api/v1/services", nil, &services)
+	return services, err
+}
+
+func (t *Client) Status(ctx context.Context) ([]ServiceStatus, error) {
+	var statuses []ServiceStatus
+	err := t.get(ctx, "/api/v1/status", nil, &statuses)
+	return statuses, err
+}
+
+func (t *Client) ServiceStatus(ctx context.Context, service string) (*ServiceStatus, error) {
+	var status ServiceStatus
+	if err := t.get(ctx, "/api/v1/status/"+url.PathEscape(service), nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Sync returns the sync progress of the services which sync a chain
+func (t *Client) Sync(ctx context.Context) ([]ServiceSync, error) {
+	var result []ServiceSync
+	err := t.get(ctx, "/api/v1/sync", nil, &result)
+	return result, err
+}
+
+// Logs downloads the logs of a service
+func (t *Client) Logs(ctx context.Context, service string, params LogsParams) ([]byte, error) {
+	query := url.Values{}
+	if params.Since != "" {
+		query.Set("since", params.Since)
+	}
+	if params.Tail != "" {
+		query.Set("tail", params.Tail)
+	}
+	return t.doRaw(ctx, http.MethodGet, "/api/v1/logs/"+url.PathEscape(service), query, nil)
+}
+
+// FollowLogs streams the logs of a service until ctx is done or the returned reader is closed
+func (t *Client) FollowLogs(ctx context.Context, service string, params LogsParams) (io.ReadCloser, error) {
+	query := url.Values{}
+	query.Set("follow", "true")
+	if params.Since != "" {
+		query.Set("since", params.Since)
+	}
+	if params.Tail != "" {
+		query.Set("tail", params.Tail)
+	}
+	resp, err := t.send(ctx, http.MethodGet, "/api/v1/logs/"+url.PathEscape(service), query, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// LauncherInfo returns the information of the attached launcher as is
+func (t *Client) LauncherInfo(ctx context.Context) (json.RawMessage, error) {
+	var info json.RawMessage
+	err := t.get(ctx, "/api/v1/info", nil, &info)
+	return info, err
+}
+
+func (t *Client) UpdateBackup(ctx context.Context, settings BackupSettings) error {
+	return t.do(ctx, http.MethodPut, "/api/v1/backup", nil, settings, nil)
+}
+
+func (t *Client) Consoles(ctx context.Context) (map[string]Console, error) {
+	var consoles map[string]Console
+	err := t.get(ctx, "/api/v1/consoles", nil, &consoles)
+	return consoles, err
+}
+
+func (t *Client) Console(ctx context.Context, id string) (*Console, error) {
+	var console Console
+	if err := t.get(ctx, "/api/v1/consoles/"+url.PathEscape(id), nil, &console); err != nil {
+		return nil, err
+	}
+	return &console, nil
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/opendexnetwork/opendex-docker-api/service/boltz/boltzrpc"
+	"github.com/opendexnetwork/opendex-docker-api/service/lnd/lnrpc"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// LndGetInfo returns the information of an lnd service (lndbtc or lndltc)
+func (t *Client) LndGetInfo(ctx context.Context, service string) (*lnrpc.GetInfoResponse, error) {
+	var resp lnrpc.GetInfoResponse
+	if err := t.get(ctx, "/api/v1/"+url.PathEscape(service)+"/getinfo", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (t *Client) BoltzServiceInfo(ctx context.Context, currency string) (*boltzrpc.GetServiceInfoResponse, error) {
+	var resp boltzrpc.GetServiceInfoResponse
+	if err := t.get(ctx, "/api/v1/boltz/service-info/"+url.PathEscape(currency), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BoltzDeposit returns an address to deposit into a channel. A zero inboundLiquidity uses the default of 50%.
+func (t *Client) BoltzDeposit(ctx context.Context, currency string, inboundLiquidity uint32) (*boltzrpc.DepositResponse, error) {
+	query := url.Values{}
+	if inboundLiquidity > 0 {
+		query.Set("inbound_liquidity", strconv.FormatUint(uint64(inboundLiquidity), 10))
+	}
+	var resp boltzrpc.DepositResponse
+	if err := t.get(ctx, "/api/v1/boltz/deposit/"+url.PathEscape(currency), query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// BoltzWithdraw withdraws amount satoshis from a channel to address with a reverse swap
+func (t *Client) BoltzWithdraw(ctx context.Context, currency string, amount int64, address string) (*boltzrpc.CreateReverseSwapResponse, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(amount, 10))
+	form.Set("address", address)
+	resp, err := t.send(ctx, http.MethodPost, "/api/v1/boltz/withdraw/"+url.PathEscape(currency), nil,
+		"application/x-www-form-urlencoded", []byte(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var result boltzrpc.CreateReverseSwapResponse
+	if err := decode(resp.Body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// JsonRpc forwards a JSON-RPC request (or a batch) to bitcoind, litecoind or geth and returns the response of the
+// node as is. Errors answered by the node with a non-2xx status (e.g. by bitcoind) are returned as *Error whose
+// message is the JSON-RPC response.
+func (t *Client) JsonRpc(ctx context.Context, service string, request interface{}) (json.RawMessage, error) {
+	return t.doRaw(ctx, http.MethodPost, "/api/v1/"+url.PathEscape(service)+"/jsonrpc", nil, request)
+}
+
+// JsonRpcMethods returns the methods which may be forwarded to the node
+func (t *Client) JsonRpcMethods(ctx context.Context, service string) ([]string, error) {
+	var result JsonRpcMethods
+	if err := t.get(ctx, "/api/v1/"+url.PathEscape(service)+"/jsonrpc/methods", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Methods, nil
+}
+
+// External probes the external node used by lnd in the external mode of bitcoind or litecoind
+func (t *Client) External(ctx context.Context, service string) (*ExternalNode, error) {
+	var result ExternalNode
+	if err := t.get(ctx, "/api/v1/"+url.PathEscape(service)+"/external", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LndSync returns the neutrino sync progress of an lnd service. It fails with 409 when lnd isn't in neutrino mode.
+func (t *Client) LndSync(ctx context.Context, service string) (*NeutrinoSyncState, error) {
+	var result NeutrinoSyncState
+	if err := t.get(ctx, "/api/v1/"+url.PathEscape(service)+"/sync", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LndConfig returns the settings of the lnd.conf of an lnd service
+func (t *Client) LndConfig(ctx context.Context, service string) (*LndConfig, error) {
+	var result LndConfig
+	if err := t.get(ctx, "/api/v1/"+url.PathEscape(service)+"/config", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	pb "github.com/opendexnetwork/opendex-docker-api/service/opendexd/opendexrpc"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// stream reads a newline delimited JSON response. A line like {"error":{"message":"..."}} ends the stream with an
+// error (see utils.StreamError).
+type stream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+	cancel context.CancelFunc
+}
+
+// openStream returns once the response headers are received. Some streams (e.g. the setup status) only start
+// after their first message so cancel ctx to give up waiting.
+func (t *Client) openStream(ctx context.Context, path string, query url.Values) (*stream, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	resp, err := t.send(ctx, http.MethodGet, path, query, "", nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return &stream{body: resp.Body, reader: bufio.NewReader(resp.Body), cancel: cancel}, nil
+}
+
+func (t *stream) next(result interface{}) error {
+	for {
+		line, err := t.reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		var e struct {
+			Error *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(line, &e) == nil && e.Error != nil {
+			return errors.New(e.Error.Message)
+		}
+		return decode(bytes.NewReader(line), result)
+	}
+}
+
+// Close ends the stream. A blocked Recv returns with an error.
+func (t *stream) Close() error {
+	t.cancel()
+	return t.body.Close()
+}
+
+// SetupStatusStream follows the setup progress. Recv returns io.EOF after the "Done" status.
+type SetupStatusStream struct {
+	*stream
+}
+
+func (t *SetupStatusStream) Recv() (*SetupStatus, error) {
+	var status SetupStatus
+	if err := t.next(&status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func (t *Client) SetupStatus(ctx context.Context) (*SetupStatusStream, error) {
+	s, err := t.openStream(ctx, "/api/v1/setup-status", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SetupStatusStream{s}, nil
+}
+
+type OrderStream struct {
+	*stream
+}
+
+func (t *OrderStream) Recv() (*pb.OrderUpdate, error) {
+	var update pb.OrderUpdate
+	if err := t.next(&update); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// SubscribeOrders follows the order book changes until ctx is done or the stream is closed. With existing the
+// active orders are sent first.
+func (t *Client) SubscribeOrders(ctx context.Context, existing bool) (*OrderStream, error) {
+	query := url.Values{}
+	if existing {
+		query.Set("existing", "true")
+	}
+	s, err := t.openStream(ctx, "/api/v1/opendexd/subscribeorders", query)
+	if err != nil {
+		return nil, err
+	}
+	return &OrderStream{s}, nil
+}
+
+type SwapStream struct {
+	*stream
+}
+
+func (t *SwapStream) Recv() (*pb.SwapSuccess, error) {
+	var swap pb.SwapSuccess
+	if err := t.next(&swap); err != nil {
+		return nil, err
+	}
+	return &swap, nil
+}
+
+// SubscribeSwaps follows the completed swaps. With includeTaker the swaps of our own orders are included.
+func (t *Client) SubscribeSwaps(ctx context.Context, includeTaker bool) (*SwapStream, error) {
+	query := url.Values{}
+	if includeTaker {
+		query.Set("includeTaker", "true")
+	}
+	s, err := t.openStream(ctx, "/api/v1/opendexd/subscribeswaps", query)
+	if err != nil {
+		return nil, err
+	}
+	return &SwapStream{s}, nil
+}
+
+// StatusStream polls the service statuses because the proxy has no status stream
+type StatusStream struct {
+	client   *Client
+	ctx      context.Context
+	cancel   context.CancelFunc
+	interval time.Duration
+
+	mutex   sync.Mutex
+	started bool
+	last    []ServiceStatus
+}
+
+// Recv returns the statuses on the first call and then blocks until any of them changes
+func (t *StatusStream) Recv() ([]ServiceStatus, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for {
+		statuses, err := t.client.Status(t.ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !t.started || !reflect.DeepEqual(statuses, t.last) {
+			t.started = true
+			t.last = statuses
+			return statuses, nil
+		}
+		select {
+		case <-t.ctx.Done():
+			return nil, t.ctx.Err()
+		case <-time.After(t.interval):
+		}
+	}
+}
+
+func (t *StatusStream) Close() error {
+	t.cancel()
+	return nil
+}
+
+func (t *Client) WatchStatus(ctx context.Context, interval time.Duration) *StatusStream {
+	ctx, cancel := context.WithCancel(ctx)
+	return &StatusStream{
+		client:   t,
+		ctx:      ctx,
+		cancel:   cancel,
+		interval: interval,
+	}
+}
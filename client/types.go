@@ -0,0 +1,558 @@
+package client
+
+import (
+	"encoding/json"
+	"github.com/opendexnetwork/opendex-docker-api/audit"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/certs"
+	"github.com/opendexnetwork/opendex-docker-api/limiter"
+	pb "github.com/opendexnetwork/opendex-docker-api/service/opendexd/opendexrpc"
+	"time"
+)
+
+// The packages of the services depend on Docker so their request and response types are mirrored here
+
+type ServiceEntry struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type SyncProgress struct {
+	Current  int64   `json:"current"`
+	Total    int64   `json:"total"`
+	Progress float64 `json:"progress"`
+	Synced   bool    `json:"synced"`
+	// Rate is in blocks per second
+	Rate float64 `json:"rate"`
+	// Eta is in seconds, -1 when unknown
+	Eta       int64     `json:"eta"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ServiceStatus struct {
+	Service string        `json:"service"`
+	Status  string        `json:"status"`
+	Sync    *SyncProgress `json:"sync,omitempty"`
+}
+
+type ServiceSync struct {
+	Service string `json:"service"`
+	SyncProgress
+}
+
+type SetupStatus struct {
+	Status  string          `json:"status"`
+	Details json.RawMessage `json:"details"`
+}
+
+type BackupSettings struct {
+	Location string
+}
+
+type Console struct {
+	Id           string `json:"id"`
+	Network      string `json:"network"`
+	ConnectionId string `json:"connectionId"`
+}
+
+type LogsParams struct {
+	// Since is a duration or a timestamp (default 1h)
+	Since string
+	// Tail is the number of lines from the end (default all)
+	Tail string
+}
+
+type CreateParams struct {
+	Password string `json:"password"`
+}
+
+type RestoreParams struct {
+	Password     string `json:"password"`
+	SeedMnemonic string `json:"seedMnemonic"`
+	BackupDir    string `json:"backupDir"`
+}
+
+type UnlockParams struct {
+	Password string `json:"password"`
+}
+
+type ChangepasswordParams struct {
+	NewPassword string `json:"newPassword"`
+	OldPassword string `json:"oldPassword"`
+}
+
+type ListOrdersParams struct {
+	PairId         string
+	Owner          pb.ListOrdersRequest_Owner
+	Limit          uint32
+	IncludeAliases bool
+}
+
+type OrderBookParams struct {
+	PairId    string
+	Precision int32
+	Limit     uint32
+}
+
+type PlaceOrderParams struct {
+	Price             float64      `json:"price"`
+	Quantity          uint64       `json:"quantity"`
+	PairId            string       `json:"pairId"`
+	OrderId           string       `json:"orderId"`
+	Side              pb.OrderSide `json:"side"`
+	ReplaceOrderId    string       `json:"replaceOrderId"`
+	ImmediateOrCancel bool         `json:"immediateOrCancel"`
+}
+
+type RemoveOrderParams struct {
+	OrderId  string `json:"orderId"`
+	Quantity uint64 `json:"quantity"`
+}
+
+type JsonRpcMethods struct {
+	Methods []string `json:"methods"`
+}
+
+type ZmqEndpoint struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ExternalNode struct {
+	Host                 string        `json:"host"`
+	Chain                string        `json:"chain"`
+	ExpectedChain        string        `json:"expectedChain"`
+	Blocks               int64         `json:"blocks"`
+	Headers              int64         `json:"headers"`
+	VerificationProgress float64       `json:"verificationProgress"`
+	InitialBlockDownload bool          `json:"initialBlockDownload"`
+	Latency              int64         `json:"latency"`
+	Zmq                  []ZmqEndpoint `json:"zmq"`
+	Error                string        `json:"error,omitempty"`
+}
+
+type Fork struct {
+	Type   string `json:"type"`
+	Active bool   `json:"active"`
+	Height int32  `json:"height,omitempty"`
+}
+
+type BlockchainInfo struct {
+	Chain                string          `json:"chain"`
+	Blocks               int32           `json:"blocks"`
+	Headers              int32           `json:"headers"`
+	BestBlockHash        string          `json:"bestblockhash"`
+	Difficulty           float64         `json:"difficulty"`
+	MedianTime           int32           `json:"mediantime"`
+	VerificationProgress float64         `json:"verificationprogress"`
+	InitialBlockDownload bool            `json:"initialblockdownload"`
+	ChainWork            string          `json:"chainwork"`
+	SizeOnDisk           int64           `json:"size_on_disk"`
+	Pruned               bool            `json:"pruned"`
+	SoftForks            map[string]Fork `json:"softforks,omitempty"`
+	Warnings             string          `json:"warnings"`
+}
+
+type NetworkAddress struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	Score   int    `json:"score"`
+}
+
+type Network struct {
+	Name      string `json:"name"`
+	Limited   bool   `json:"limited"`
+	Reachable bool   `json:"reachable"`
+	Proxy     string `json:"proxy"`
+}
+
+type NetworkInfo struct {
+	Version         int32            `json:"version"`
+	SubVersion      string           `json:"subversion"`
+	ProtocolVersion int32            `json:"protocolversion"`
+	LocalServices   string           `json:"localservices"`
+	LocalRelay      bool             `json:"localrelay"`
+	TimeOffset      int64            `json:"timeoffset"`
+	NetworkActive   bool             `json:"networkactive"`
+	Connections     int32            `json:"connections"`
+	Networks        []Network        `json:"networks"`
+	RelayFee        float64          `json:"relayfee"`
+	IncrementalFee  float64          `json:"incrementalfee"`
+	LocalAddresses  []NetworkAddress `json:"localaddresses"`
+	Warnings        string           `json:"warnings"`
+}
+
+type PeerInfo struct {
+	Id             int32   `json:"id"`
+	Addr           string  `json:"addr"`
+	AddrLocal      string  `json:"addrlocal,omitempty"`
+	Services       string  `json:"services"`
+	RelayTxes      bool    `json:"relaytxes"`
+	LastSend       int64   `json:"lastsend"`
+	LastRecv       int64   `json:"lastrecv"`
+	BytesSent      int64   `json:"bytessent"`
+	BytesRecv      int64   `json:"bytesrecv"`
+	ConnTime       int64   `json:"conntime"`
+	TimeOffset     int64   `json:"timeoffset"`
+	PingTime       float64 `json:"pingtime"`
+	Version        int32   `json:"version"`
+	SubVer         string  `json:"subver"`
+	Inbound        bool    `json:"inbound"`
+	StartingHeight int32   `json:"startingheight"`
+	SyncedHeaders  int32   `json:"synced_headers"`
+	SyncedBlocks   int32   `json:"synced_blocks"`
+}
+
+type MempoolInfo struct {
+	Loaded        bool    `json:"loaded"`
+	Size          int64   `json:"size"`
+	Bytes         int64   `json:"bytes"`
+	Usage         int64   `json:"usage"`
+	MaxMempool    int64   `json:"maxmempool"`
+	MempoolMinFee float64 `json:"mempoolminfee"`
+	MinRelayTxFee float64 `json:"minrelaytxfee"`
+}
+
+type BlockHeader struct {
+	Hash              string  `json:"hash"`
+	Confirmations     int64   `json:"confirmations"`
+	Height            int32   `json:"height"`
+	Version           int32   `json:"version"`
+	MerkleRoot        string  `json:"merkleroot"`
+	Time              int64   `json:"time"`
+	MedianTime        int64   `json:"mediantime"`
+	Nonce             uint32  `json:"nonce"`
+	Bits              string  `json:"bits"`
+	Difficulty        float64 `json:"difficulty"`
+	ChainWork         string  `json:"chainwork"`
+	NTx               int32   `json:"nTx"`
+	PreviousBlockHash string  `json:"previousblockhash,omitempty"`
+	NextBlockHash     string  `json:"nextblockhash,omitempty"`
+}
+
+// Block is the result of getblock with verbosity 1, the transactions are their ids
+type Block struct {
+	BlockHeader
+	StrippedSize int32    `json:"strippedsize"`
+	Size         int32    `json:"size"`
+	Weight       int32    `json:"weight"`
+	Tx           []string `json:"tx"`
+}
+
+type ScriptSig struct {
+	Asm string `json:"asm"`
+	Hex string `json:"hex"`
+}
+
+type TxIn struct {
+	Coinbase    string    `json:"coinbase,omitempty"`
+	TxId        string    `json:"txid,omitempty"`
+	Vout        uint32    `json:"vout"`
+	ScriptSig   ScriptSig `json:"scriptSig"`
+	TxInWitness []string  `json:"txinwitness,omitempty"`
+	Sequence    uint32    `json:"sequence"`
+}
+
+type ScriptPubKey struct {
+	Asm       string   `json:"asm"`
+	Hex       string   `json:"hex"`
+	ReqSigs   int32    `json:"reqSigs,omitempty"`
+	Type      string   `json:"type"`
+	Address   string   `json:"address,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+type TxOut struct {
+	Value        float64      `json:"value"`
+	N            uint32       `json:"n"`
+	ScriptPubKey ScriptPubKey `json:"scriptPubKey"`
+}
+
+// RawTransaction is the result of getrawtransaction with verbose set
+type RawTransaction struct {
+	TxId          string  `json:"txid"`
+	Hash          string  `json:"hash"`
+	Version       int32   `json:"version"`
+	Size          int32   `json:"size"`
+	VSize         int32   `json:"vsize"`
+	Weight        int32   `json:"weight"`
+	LockTime      uint32  `json:"locktime"`
+	Vin           []TxIn  `json:"vin"`
+	Vout          []TxOut `json:"vout"`
+	Hex           string  `json:"hex"`
+	BlockHash     string  `json:"blockhash,omitempty"`
+	Confirmations int64   `json:"confirmations,omitempty"`
+	Time          int64   `json:"time,omitempty"`
+	BlockTime     int64   `json:"blocktime,omitempty"`
+}
+
+// FeeEstimate is the result of estimatesmartfee. The fee rate is in BTC (or LTC) per kvB.
+type FeeEstimate struct {
+	FeeRate float64  `json:"feerate,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+	Blocks  int32    `json:"blocks"`
+}
+
+type ProviderHealth struct {
+	Url       string    `json:"url"`
+	Healthy   bool      `json:"healthy"`
+	ChainId   int64     `json:"chainId"`
+	Block     int64     `json:"block"`
+	BlockLag  int64     `json:"blockLag"`
+	Latency   int64     `json:"latency"`
+	ErrorRate float64   `json:"errorRate"`
+	Error     string    `json:"error,omitempty"`
+	LastProbe time.Time `json:"lastProbe"`
+	Rank      int       `json:"rank"`
+}
+
+type Providers struct {
+	// Current is the provider of connext, Recommended the best healthy light provider
+	Current     string           `json:"current"`
+	Mode        string           `json:"mode"`
+	Recommended string           `json:"recommended"`
+	Failover    bool             `json:"failover"`
+	Providers   []ProviderHealth `json:"providers"`
+}
+
+type SwitchProviderParams struct {
+	Provider string `json:"provider"`
+}
+
+type Chain struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type ChainProvider struct {
+	Chain
+	Url string `json:"url"`
+	// Expected is set for the chain of the network
+	Expected bool `json:"expected"`
+}
+
+type Chains struct {
+	Network   string          `json:"network"`
+	Chain     Chain           `json:"chain"`
+	Providers []ChainProvider `json:"providers"`
+}
+
+type NodeConfig struct {
+	Index            int                          `json:"index"`
+	PublicIdentifier string                       `json:"publicIdentifier"`
+	SignerAddress    string                       `json:"signerAddress"`
+	ChainAddresses   map[string]map[string]string `json:"chainAddresses"`
+}
+
+type Balance struct {
+	Amount []string `json:"amount"`
+	To     []string `json:"to"`
+}
+
+type NetworkContext struct {
+	ChainId                 int64  `json:"chainId"`
+	ChannelFactoryAddress   string `json:"channelFactoryAddress"`
+	TransferRegistryAddress string `json:"transferRegistryAddress"`
+}
+
+type ChannelState struct {
+	ChannelAddress     string          `json:"channelAddress"`
+	Alice              string          `json:"alice"`
+	Bob                string          `json:"bob"`
+	AliceIdentifier    string          `json:"aliceIdentifier"`
+	BobIdentifier      string          `json:"bobIdentifier"`
+	AssetIds           []string        `json:"assetIds"`
+	Balances           []Balance       `json:"balances"`
+	ProcessedDepositsA []string        `json:"processedDepositsA"`
+	ProcessedDepositsB []string        `json:"processedDepositsB"`
+	DefundNonces       []string        `json:"defundNonces"`
+	Timeout            string          `json:"timeout"`
+	Nonce              int64           `json:"nonce"`
+	InDispute          bool            `json:"inDispute"`
+	NetworkContext     NetworkContext  `json:"networkContext"`
+	LatestUpdate       json.RawMessage `json:"latestUpdate,omitempty"`
+}
+
+// AssetBalance amounts are in wei
+type AssetBalance struct {
+	AssetId           string `json:"assetId"`
+	Local             string `json:"local"`
+	Remote            string `json:"remote"`
+	ProcessedDeposits string `json:"processedDeposits"`
+}
+
+type Transfer struct {
+	TransferId          string          `json:"transferId"`
+	ChannelAddress      string          `json:"channelAddress"`
+	ChainId             int64           `json:"chainId"`
+	AssetId             string          `json:"assetId"`
+	Balance             Balance         `json:"balance"`
+	Initiator           string          `json:"initiator"`
+	Responder           string          `json:"responder"`
+	InitiatorIdentifier string          `json:"initiatorIdentifier"`
+	ResponderIdentifier string          `json:"responderIdentifier"`
+	TransferDefinition  string          `json:"transferDefinition"`
+	TransferTimeout     string          `json:"transferTimeout"`
+	TransferState       json.RawMessage `json:"transferState,omitempty"`
+	TransferResolver    json.RawMessage `json:"transferResolver,omitempty"`
+	ChannelNonce        int64           `json:"channelNonce"`
+	InDispute           bool            `json:"inDispute"`
+	Meta                json.RawMessage `json:"meta,omitempty"`
+}
+
+type TransferFilter struct {
+	ChannelAddress string
+	// Active returns the unresolved transfers of the channel
+	Active bool
+}
+
+type DepositParams struct {
+	ChannelAddress string `json:"channelAddress"`
+	AssetId        string `json:"assetId"`
+}
+
+type DepositResult struct {
+	ChannelAddress  string `json:"channelAddress"`
+	TransactionHash string `json:"transactionHash,omitempty"`
+}
+
+type WithdrawParams struct {
+	ChannelAddress string `json:"channelAddress"`
+	AssetId        string `json:"assetId"`
+	// Amount and Fee are in wei
+	Amount    string `json:"amount"`
+	Recipient string `json:"recipient"`
+	Fee       string `json:"fee"`
+}
+
+type WithdrawResult struct {
+	ChannelAddress  string `json:"channelAddress"`
+	TransferId      string `json:"transferId"`
+	TransactionHash string `json:"transactionHash,omitempty"`
+}
+
+type TokenBalance struct {
+	Symbol string `json:"symbol"`
+	// Contract is empty for ether
+	Contract string `json:"contract,omitempty"`
+	Decimals int    `json:"decimals"`
+	// Balance is in the smallest unit (wei for ether), Amount in whole tokens
+	Balance string `json:"balance"`
+	Amount  string `json:"amount"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Balances struct {
+	Address  string         `json:"address"`
+	Mode     string         `json:"mode"`
+	Provider string         `json:"provider"`
+	Ether    TokenBalance   `json:"ether"`
+	Tokens   []TokenBalance `json:"tokens"`
+}
+
+type SyncState struct {
+	Mode     string `json:"mode"`
+	Provider string `json:"provider"`
+	Syncing  bool   `json:"syncing"`
+	// CurrentBlock and HighestBlock are the block number when the node isn't syncing
+	StartingBlock int64 `json:"startingBlock"`
+	CurrentBlock  int64 `json:"currentBlock"`
+	HighestBlock  int64 `json:"highestBlock"`
+	// KnownStates and PulledStates are 0 on geth 1.11 and later
+	KnownStates   int64  `json:"knownStates"`
+	PulledStates  int64  `json:"pulledStates"`
+	Peers         int64  `json:"peers"`
+	ClientVersion string `json:"clientVersion"`
+}
+
+// NeutrinoSyncState is the sync progress of an lnd service in neutrino mode
+type NeutrinoSyncState struct {
+	// FilterHeaders is the cfheaders height and BlockHeight the height of the peer neutrino syncs to
+	FilterHeaders int64   `json:"filterHeaders"`
+	BlockHeight   int64   `json:"blockHeight"`
+	Progress      float64 `json:"progress"`
+	Synced        bool    `json:"synced"`
+	// Height is the height of the last block lnd was notified of, 0 until the wallet is unlocked
+	Height int64 `json:"height"`
+}
+
+type LndConfig struct {
+	// Backend is the chain backend: neutrino, bitcoind or litecoind (btcd or ltcd on simnet)
+	Backend string `json:"backend"`
+	Network string `json:"network"`
+	// RpcHost, ZmqPubRawBlock and ZmqPubRawTx are the settings of a full node backend
+	RpcHost        string `json:"rpcHost,omitempty"`
+	ZmqPubRawBlock string `json:"zmqPubRawBlock,omitempty"`
+	ZmqPubRawTx    string `json:"zmqPubRawTx,omitempty"`
+	// NeutrinoPeers are the neutrino.connect and neutrino.addpeer peers
+	NeutrinoPeers []string `json:"neutrinoPeers,omitempty"`
+	Alias         string   `json:"alias"`
+	Color         string   `json:"color"`
+	Listen        []string `json:"listen"`
+	RpcListen     []string `json:"rpcListen"`
+	RestListen    []string `json:"restListen"`
+}
+
+type DoctorCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+type DoctorReport struct {
+	Checks   []DoctorCheck `json:"checks"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+}
+
+type ServiceSettings struct {
+	Name     string                 `json:"name"`
+	Disabled bool                   `json:"disabled"`
+	Mode     string                 `json:"mode,omitempty"`
+	Modes    []string               `json:"modes,omitempty"`
+	External map[string]interface{} `json:"external,omitempty"`
+}
+
+// SettingsChange changes the settings of a service. Nil fields are kept and a nil external setting is removed.
+type SettingsChange struct {
+	Disabled *bool                  `json:"disabled,omitempty"`
+	Mode     *string                `json:"mode,omitempty"`
+	External map[string]interface{} `json:"external,omitempty"`
+}
+
+type SettingDiff struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type SettingsPreview struct {
+	Service  string        `json:"service"`
+	Changes  []SettingDiff `json:"changes"`
+	Recreate []string      `json:"recreate"`
+}
+
+type Token = auth.Token
+type CreateTokenParams = auth.CreateTokenParams
+type CreateTokenResult = auth.CreateTokenResult
+
+type AuditRecord = audit.Record
+
+type AuditFilter struct {
+	Since  time.Time
+	Until  time.Time
+	Caller string
+	Method string
+	// Route is the route pattern, e.g. /api/v1/opendexd/placeorder
+	Route string
+	Limit int
+}
+
+type Lockout = limiter.Lockout
+
+type TlsInfo = certs.Info
+type ClientCertificate = certs.Client
+type IssueParams = certs.IssueParams
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/client"
+	"github.com/opendexnetwork/opendex-docker-api/doctor"
+	pb "github.com/opendexnetwork/opendex-docker-api/service/opendexd/opendexrpc"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/crypto/ssh/terminal"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"software.sslmate.com/src/go-pkcs12"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// CliOptions are the options of the subcommands which talk to a running proxy
+type CliOptions struct {
+	Url                string
+	Token              string
+	Output             string
+	Insecure           bool
+	ClientCert         string
+	ClientCertPassword string
+	Timeout            time.Duration
+}
+
+var cli = CliOptions{
+	Output:  "table",
+	Timeout: 30 * time.Second,
+}
+
+func (t *CliOptions) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&t.Url, "url", t.Url, "The URL of the proxy (default http(s)://127.0.0.1:<port>)")
+	fs.StringVar(&t.Token, "token", t.Token, "The API token (default <proxy-dir>/admin.token)")
+	fs.StringVarP(&t.Output, "output", "o", t.Output, "The output format: table or json")
+	fs.BoolVar(&t.Insecure, "insecure", t.Insecure, "Skip verifying the TLS certificate of the proxy")
+	fs.StringVar(&t.ClientCert, "client-cert", t.ClientCert, "The PKCS#12 client certificate for --mtls (default <proxy-dir>/ca/admin.p12)")
+	fs.StringVar(&t.ClientCertPassword, "client-cert-password", t.ClientCertPassword, "The password of --client-cert (default read from <client-cert>.password)")
+	fs.DurationVar(&t.Timeout, "timeout", t.Timeout, "The timeout of a request")
+}
+
+func (t *CliOptions) tlsConfig() (*tls.Config, error) {
+	result := &tls.Config{InsecureSkipVerify: t.Insecure}
+
+	certFile := cfg.ProxyFile("tls.crt")
+	if cfg.TlsCert != "" {
+		certFile = cfg.TlsCert
+	}
+	if data, err := ioutil.ReadFile(certFile); err == nil {
+		// trust the self-signed certificate of the proxy in addition to the system roots
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(data)
+		result.RootCAs = pool
+	}
+
+	bundleFile := t.ClientCert
+	if bundleFile == "" && cfg.Mtls {
+		bundleFile = filepath.Join(cfg.ProxyFile("ca"), "admin.p12")
+	}
+	if bundleFile == "" {
+		return result, nil
+	}
+	bundle, err := ioutil.ReadFile(bundleFile)
+	if err != nil {
+		return nil, err
+	}
+	password := t.ClientCertPassword
+	if password == "" {
+		data, err := ioutil.ReadFile(bundleFile + ".password")
+		if err != nil {
+			return nil, fmt.Errorf("--client-cert-password is required: %s", err)
+		}
+		password = strings.TrimSpace(string(data))
+	}
+	key, cert, _, err := pkcs12.DecodeChain(bundle, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %s", bundleFile, err)
+	}
+	result.Certificates = []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key, Leaf: cert}}
+	return result, nil
+}
+
+func (t *CliOptions) NewClient() (*client.Client, error) {
+	baseUrl := t.Url
+	if baseUrl == "" {
+		scheme := "http"
+		if cfg.Tls {
+			scheme = "https"
+		}
+		baseUrl = fmt.Sprintf("%s://127.0.0.1:%d", scheme, cfg.Port)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if strings.HasPrefix(baseUrl, "https://") {
+		config, err := t.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = config
+	}
+	options := client.Options{HttpClient: &http.Client{Transport: transport}}
+
+	token := t.Token
+	if token == "" {
+		if data, err := ioutil.ReadFile(cfg.ProxyFile("admin.token")); err == nil {
+			token = strings.TrimSpace(string(data))
+		}
+	}
+	if token != "" {
+		options.Auth = client.BearerToken(token)
+	}
+	return client.New(baseUrl, options), nil
+}
+
+func (t *CliOptions) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), t.Timeout)
+}
+
+// print writes value as indented JSON or calls table with a tab separated writer
+func (t *CliOptions) print(value interface{}, table func(w io.Writer)) error {
+	switch t.Output {
+	case "json":
+		if msg, ok := value.(proto.Message); ok {
+			m := jsonpb.Marshaler{EmitDefaults: true, Indent: "  "}
+			if err := m.Marshal(os.Stdout, msg); err != nil {
+				return err
+			}
+			fmt.Println()
+			return nil
+		}
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case "table":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	default:
+		return fmt.Errorf("invalid output format %s", t.Output)
+	}
+}
+
+// quietLogs keeps the standard output of the subcommands for their results
+func quietLogs() {
+	logrus.SetOutput(os.Stderr)
+	logrus.SetLevel(logrus.WarnLevel)
+}
+
+// cliCommand loads the configuration (so that the defaults follow --proxy-dir, --port and --tls) and runs f with a
+// client of the running proxy
+func cliCommand(f func(c *client.Client, args []string) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		quietLogs()
+		if err := cfg.Load(cmd.Flags()); err != nil {
+			return err
+		}
+		c, err := cli.NewClient()
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true
+		return f(c, args)
+	}
+}
+
+func newStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the status of the services",
+		Args:  cobra.NoArgs,
+		RunE: cliCommand(func(c *client.Client, args []string) error {
+			ctx, cancel := cli.context()
+			defer cancel()
+			statuses, err := c.Status(ctx)
+			if err != nil {
+				return err
+			}
+			return cli.print(statuses, func(w io.Writer) {
+				fmt.Fprintln(w, "SERVICE\tSTATUS\tETA")
+				for _, s := range statuses {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", s.Service, s.Status, formatEta(s.Sync))
+				}
+			})
+		}),
+	}
+	cli.BindFlags(cmd.Flags())
+	return cmd
+}
+
+// formatEta formats the sync ETA of a service, e.g. "2h15m0s (310.5 blocks/s)"
+func formatEta(sync *client.SyncProgress) string {
+	if sync == nil || sync.Synced {
+		return ""
+	}
+	if sync.Eta < 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%.1f blocks/s)", time.Duration(sync.Eta)*time.Second, sync.Rate)
+}
+
+func newLogsCommand() *cobra.Command {
+	var params client.LogsParams
+	var follow bool
+	cmd := &cobra.Command{
+		Use:   "logs <service>",
+		Short: "Print the logs of a service",
+		Args:  cobra.ExactArgs(1),
+		RunE: cliCommand(func(c *client.Client, args []string) error {
+			if follow {
+				r, err := c.FollowLogs(context.Background(), args[0], params)
+				if err != nil {
+					return err
+				}
+				defer r.Close()
+				_, err = io.Copy(os.Stdout, r)
+				return err
+			}
+			ctx, cancel := cli.context()
+			defer cancel()
+			logs, err := c.Logs(ctx, args[0], params)
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(logs)
+			return err
+		}),
+	}
+	cli.BindFlags(cmd.Flags())
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing new lines")
+	cmd.Flags().StringVar(&params.Since, "since", "", "A duration or timestamp (default 1h)")
+	cmd.Flags().StringVar(&params.Tail, "tail", "", "The number of lines from the end (default all)")
+	return cmd
+}
+
+func newOrdersCommand() *cobra.Command {
+	var params client.ListOrdersParams
+	var owner string
+	cmd := &cobra.Command{
+		Use:   "orders",
+		Short: "List the orders of the order book",
+		Args:  cobra.NoArgs,
+		RunE: cliCommand(func(c *client.Client, args []string) error {
+			value, ok := pb.ListOrdersRequest_Owner_value[strings.ToUpper(owner)]
+			if !ok {
+				return fmt.Errorf("invalid owner %s", owner)
+			}
+			params.Owner = pb.ListOrdersRequest_Owner(value)
+			ctx, cancel := cli.context()
+			defer cancel()
+			resp, err := c.ListOrders(ctx, params)
+			if err != nil {
+				return err
+			}
+			return cli.print(resp, func(w io.Writer) {
+				var pairs []string
+				for pair := range resp.Orders {
+					pairs = append(pairs, pair)
+				}
+				sort.Strings(pairs)
+				fmt.Fprintln(w, "PAIR\tSIDE\tPRICE\tQUANTITY\tOWN\tID")
+				for _, pair := range pairs {
+					orders := resp.Orders[pair]
+					for _, o := range append(orders.BuyOrders, orders.SellOrders...) {
+						fmt.Fprintf(w, "%s\t%s\t%v\t%d\t%v\t%s\n", pair, o.Side, o.Price, o.Quantity, o.IsOwnOrder, o.Id)
+					}
+				}
+			})
+		}),
+	}
+	cli.BindFlags(cmd.Flags())
+	cmd.Flags().StringVar(&params.PairId, "pair", "", "The trading pair, e.g. LTC/BTC (default all)")
+	cmd.Flags().StringVar(&owner, "owner", "both", "both, own or peer")
+	cmd.Flags().Uint32Var(&params.Limit, "limit", 0, "The maximum number of orders per pair and side (0 for all)")
+	return cmd
+}
+
+// readPassword prompts on the terminal or reads the first line of the standard input when it is piped
+func readPassword(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if terminal.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, prompt)
+		password, err := terminal.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(password), err
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func newUnlockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unlock",
+		Short: "Unlock opendexd with the password read from the terminal or the standard input",
+		Args:  cobra.NoArgs,
+		RunE: cliCommand(func(c *client.Client, args []string) error {
+			password, err := readPassword("Password: ")
+			if err != nil {
+				return err
+			}
+			ctx, cancel := cli.context()
+			defer cancel()
+			resp, err := c.Unlock(ctx, client.UnlockParams{Password: password})
+			if err != nil {
+				return err
+			}
+			return cli.print(resp, func(w io.Writer) {
+				fmt.Fprintln(w, "LND\tSTATUS")
+				for _, lnd := range resp.UnlockedLnds {
+					fmt.Fprintf(w, "%s\tunlocked\n", lnd)
+				}
+				for _, lnd := range resp.LockedLnds {
+					fmt.Fprintf(w, "%s\tlocked\n", lnd)
+				}
+			})
+		}),
+	}
+	cli.BindFlags(cmd.Flags())
+	return cmd
+}
+
+func printTokens(w io.Writer, tokens []client.Token) {
+	fmt.Fprintln(w, "ID\tNAME\tROLE\tCREATED")
+	for _, token := range tokens {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", token.Id, token.Name, token.Role, token.CreatedAt.Format(time.RFC3339))
+	}
+}
+
+func newTokenCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Manage the API tokens",
+	}
+
+	var role string
+	create := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create an API token and print its secret",
+		Args:  cobra.ExactArgs(1),
+		RunE: cliCommand(func(c *client.Client, args []string) error {
+			ctx, cancel := cli.context()
+			defer cancel()
+			result, err := c.CreateToken(ctx, client.CreateTokenParams{Name: args[0], Role: role})
+			if err != nil {
+				return err
+			}
+			return cli.print(result, func(w io.Writer) {
+				printTokens(w, []client.Token{result.Token})
+				fmt.Fprintf(w, "\nSecret: %s\n", result.Secret)
+			})
+		}),
+	}
+	cli.BindFlags(create.Flags())
+	create.Flags().StringVar(&role, "role", string(auth.RoleReadonly), "readonly, trader or admin")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the API tokens",
+		Args:  cobra.NoArgs,
+		RunE: cliCommand(func(c *client.Client, args []string) error {
+			ctx, cancel := cli.context()
+			defer cancel()
+			tokens, err := c.Tokens(ctx)
+			if err != nil {
+				return err
+			}
+			return cli.print(tokens, func(w io.Writer) {
+				printTokens(w, tokens)
+			})
+		}),
+	}
+	cli.BindFlags(list.Flags())
+
+	revoke := &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API token",
+		Args:  cobra.ExactArgs(1),
+		RunE: cliCommand(func(c *client.Client, args []string) error {
+			ctx, cancel := cli.context()
+			defer cancel()
+			return c.RevokeToken(ctx, args[0])
+		}),
+	}
+	cli.BindFlags(revoke.Flags())
+
+	cmd.AddCommand(create, list, revoke)
+	return cmd
+}
+
+func newDoctorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check config.json, the TLS certificates, macaroons, RPC ports and Docker and suggest fixes",
+		Long: "Check config.json, the TLS certificates, macaroons, RPC ports and Docker and suggest fixes. It runs " +
+			"without a proxy (use --network-dir or --config like the server) and exits with 1 when a check fails.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			quietLogs()
+			if err := cfg.Load(cmd.Flags()); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			ctx, cancel := cli.context()
+			defer cancel()
+			report := doctor.New(cfg, nil).Run(ctx)
+			err := cli.print(report, func(w io.Writer) {
+				fmt.Fprintln(w, "STATUS\tCHECK\tMESSAGE")
+				for _, check := range report.Checks {
+					fmt.Fprintf(w, "%s\t%s\t%s\n", strings.ToUpper(string(check.Status)), check.Name, check.Message)
+					if check.Fix != "" {
+						fmt.Fprintf(w, "\t\t-> %s\n", check.Fix)
+					}
+				}
+				fmt.Fprintf(w, "\n%d error(s), %d warning(s)\n", report.Errors, report.Warnings)
+			})
+			if err != nil {
+				return err
+			}
+			if report.Errors > 0 {
+				cmd.SilenceErrors = true
+				return fmt.Errorf("%d check(s) failed", report.Errors)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&cli.Output, "output", "o", cli.Output, "The output format: table or json")
+	cmd.Flags().DurationVar(&cli.Timeout, "timeout", cli.Timeout, "The timeout of all checks")
+	return cmd
+}
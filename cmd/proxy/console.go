@@ -7,10 +7,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	socketio "github.com/googollee/go-socket.io"
+	"github.com/opendexnetwork/opendex-docker-api/audit"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"sync"
+	"time"
 )
 
 var (
@@ -19,15 +23,11 @@ var (
 )
 
 type Console struct {
-	Id           string   `json:"id"`
-	Network      string   `json:"network"`
-	ConnectionId string   `json:"connectionId"`
-	Pty          *os.File `json:"-"`
-}
-
-func init() {
-	router.GET("/api/v1/consoles", listConsoles)
-	router.GET("/api/v1/consoles/:id", getConsole)
+	Id           string    `json:"id"`
+	Network      string    `json:"network"`
+	ConnectionId string    `json:"connectionId"`
+	Pty          *os.File  `json:"-"`
+	Cmd          *exec.Cmd `json:"-"`
 }
 
 func findById(id string) *Console {
@@ -166,8 +166,8 @@ Boltzcli shortcut commands
   <amount> <address>                        withdraw from boltz channel
 `
 
-func writeInitScript(network string) {
-	f, err := os.Create("init.bash")
+func writeInitScript(file string, network string) {
+	f, err := os.Create(file)
 	if err != nil {
 		logger.Errorf("Failed to write init.bash: %s", err)
 		return
@@ -281,8 +281,9 @@ alias walletwithdraw='opendex-cli walletwithdraw'
 }
 
 func startShell(console *Console, size TerminalSize) error {
-	writeInitScript(console.Network)
-	c := exec.Command("/bin/bash", "--init-file", "init.bash")
+	initScript := cfg.ProxyFile("init.bash")
+	writeInitScript(initScript, console.Network)
+	c := exec.Command("/bin/bash", "--init-file", initScript)
 
 	ptmx, err := pty.StartWithSize(c, &pty.Winsize{Cols: size.Cols, Rows: size.Rows, X: 0, Y: 0})
 	if err != nil {
@@ -290,17 +291,74 @@ func startShell(console *Console, size TerminalSize) error {
 	}
 
 	console.Pty = ptmx
+	console.Cmd = c
+
+	// findById returns a copy
+	mutex.Lock()
+	consoleMap[console.Id] = *console
+	mutex.Unlock()
 
 	return nil
 }
 
+// killConsoles terminates the shells of all consoles. It is used when shutting down.
+func killConsoles() {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for id, console := range consoleMap {
+		if console.Cmd != nil && console.Cmd.Process != nil {
+			if err := console.Cmd.Process.Kill(); err != nil {
+				logger.Errorf("Failed to kill console %s: %s", id, err)
+			}
+		}
+		if console.Pty != nil {
+			_ = console.Pty.Close()
+		}
+		delete(consoleMap, id)
+	}
+}
+
+func auditConsole(s socketio.Conn, console *Console, err error) {
+	record := audit.Record{
+		Time:    time.Now().UTC(),
+		Caller:  "unknown",
+		Method:  "WS",
+		Route:   "/socket.io/#start",
+		Path:    "/socket.io/",
+		Params:  map[string]interface{}{"id": console.Id},
+		Status:  http.StatusOK,
+		Outcome: "success",
+	}
+	if host, _, e := net.SplitHostPort(s.RemoteAddr().String()); e == nil {
+		record.ClientIp = host
+	}
+	if identity, ok := s.Context().(*auth.Identity); ok {
+		record.Caller = identity.Name
+		record.TokenId = identity.TokenId
+		record.Role = string(identity.Role)
+	}
+	if err != nil {
+		record.Status = http.StatusInternalServerError
+		record.Outcome = "failure"
+		record.Error = err.Error()
+	}
+	auditLogger.Log(record)
+}
+
 func initSioConsole() {
+	router.GET("/api/v1/consoles", listConsoles)
+	router.GET("/api/v1/consoles/:id", getConsole)
+
+	spec.Route(http.MethodGet, "/api/v1/consoles", "List the web consoles").
+		Tag("console").Returns(map[string]Console{})
+	spec.Route(http.MethodGet, "/api/v1/consoles/:id", "Get a web console").
+		Tag("console").PathParam("id", "The console id").Returns(Console{})
+
 	sioServer.OnEvent("/", "create", func(s socketio.Conn, data string) {
-		network := os.Getenv("NETWORK")
 		id := fmt.Sprint(uuid.New())
 		console := Console{
 			Id:           id,
-			Network:      network,
+			Network:      cfg.Network,
 			ConnectionId: s.ID(),
 		}
 		consoleMap[id] = console
@@ -325,6 +383,7 @@ func initSioConsole() {
 			return
 		}
 		err = startShell(console, req.Size)
+		auditConsole(s, console, err)
 		if err != nil {
 			s.Emit("start", fmt.Sprintf("failed to start: %s", err))
 			return
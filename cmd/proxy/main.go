@@ -1,34 +1,58 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
 	"fmt"
+	"github.com/opendexnetwork/opendex-docker-api/audit"
+	"github.com/opendexnetwork/opendex-docker-api/build"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/certs"
+	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/doctor"
+	"github.com/opendexnetwork/opendex-docker-api/gateway"
 	"github.com/opendexnetwork/opendex-docker-api/launcher"
+	"github.com/opendexnetwork/opendex-docker-api/limiter"
 	"github.com/opendexnetwork/opendex-docker-api/logging"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
 	"github.com/opendexnetwork/opendex-docker-api/service"
-	"github.com/docker/docker/pkg/homedir"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	socketio "github.com/googollee/go-socket.io"
-	"github.com/opendexnetwork/opendex-docker-api/utils"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 )
 
 var (
-	logger    = initLogger()
-	router    = initRouter()
-	sioServer *socketio.Server
+	logger        = initLogger()
+	authenticator = auth.NewAuthenticator(auth.DefaultPolicy())
+	auditLogger   = audit.NewLogger()
+	rateLimiter   = limiter.NewLimiter(limiter.DefaultRules())
+	router        *gin.Engine
+	sioServer     *socketio.Server
+	manager       *service.Manager
+	grpcGateway   *gateway.Gateway
+	spec          = openapi.NewSpec("opendex-docker-api", build.Version)
 
-	port uint16
-	tls bool
-	network string
+	cfg = config.DefaultConfig()
+
+	tlsConfig *tls.Config
+	clientCA  *certs.CA
 )
 
+const sioNotifyDelay = 500 * time.Millisecond
+
 func initLogger() *logrus.Entry {
 	logrus.SetLevel(logrus.DebugLevel)
 	logrus.SetFormatter(&logging.Formatter{
@@ -48,6 +72,9 @@ func (MyWriter) Write(p []byte) (int, error) {
 
 func initRouter() *gin.Engine {
 	r := gin.New()
+	// the proxy isn't deployed behind a reverse proxy, so X-Forwarded-For and X-Real-Ip are set by the clients
+	// themselves and must not be trusted for the rate limits and the audit log
+	r.ForwardedByClientIP = false
 
 	// Configuring Gin middlewares
 	//r.Use(ginlogrus.Logger(logrus.StandardLogger()))
@@ -60,7 +87,7 @@ func initRouter() *gin.Engine {
 			c.JSON(404, gin.H{"message": "not found"})
 		} else {
 			// redirect other non-API requests to ui/index.html to fit SPA requirements
-			c.File("ui/index.html")
+			c.File(filepath.Join(cfg.UiDir, "index.html"))
 		}
 	})
 	r.NoMethod(func(c *gin.Context) {
@@ -69,6 +96,12 @@ func initRouter() *gin.Engine {
 
 	setupCors(r)
 
+	// CORS preflight requests don't carry tokens so authentication goes after CORS. Auditing and rate limiting go
+	// before authentication to record rejected requests and to lock out clients guessing tokens.
+	r.Use(auditLogger.Middleware())
+	r.Use(rateLimiter.Middleware())
+	r.Use(authenticator.Middleware())
+
 	return r
 }
 
@@ -80,12 +113,15 @@ func setupCors(r gin.IRouter) {
 	// - Preflight requests cached for 12 hours
 	config := cors.DefaultConfig()
 	config.AllowOrigins = []string{"*"}
+	// gRPC-Web clients send their metadata as headers and read the status from the response headers
+	config.AddAllowHeaders(gateway.WebHeaders...)
+	config.AddExposeHeaders(gateway.WebExposedHeaders...)
 
 	r.Use(cors.New(config))
 }
 
 func initSioServer() {
-	server, err := NewSioServer(network)
+	server, err := NewSioServer(cfg.Network)
 	if err != nil {
 		logger.Fatal(err)
 	}
@@ -95,12 +131,6 @@ func initSioServer() {
 
 	go func() {
 		err := server.Serve()
-		defer func() {
-			err := server.Close()
-			if err != nil {
-				logger.Fatalf("Failed to close socket.io server: %s", err)
-			}
-		}()
 		if err != nil {
 			logger.Fatal("Failed to start socket.io server")
 		}
@@ -116,81 +146,340 @@ func initLauncherWs() {
 
 	go launcher.StartLauncherRegistry()
 
-	launcher.ConfigureRouter(router)
+	launcher.ConfigureRouter(router, cfg.UiDir)
+	launcher.ConfigureSpec(spec)
 }
 
 func initServiceManager() {
 	logger.Debug("Creating service manager")
-	manager, err := service.NewManager(network)
+	m, err := service.NewManager(cfg)
 	if err != nil {
 		logger.Fatalf("Failed to create service manager: %s", err)
 	}
-	defer func() {
-		err := manager.Close()
+	manager = m
+
+	manager.ConfigureRouter(router)
+	manager.ConfigureSpec(spec)
+}
+
+func initAuth() {
+	if cfg.Auth {
+		store, err := auth.NewTokenStore(cfg.ProxyFile("tokens.json"))
 		if err != nil {
-			logger.Fatalf("Failed to close service manager: %s", err)
+			logger.Fatalf("Failed to load tokens: %s", err)
 		}
-	}()
+		if store.Len() == 0 {
+			// bootstrap an admin token so that the operator can create other tokens
+			_, secret, err := store.Create("admin", auth.RoleAdmin)
+			if err != nil {
+				logger.Fatalf("Failed to create admin token: %s", err)
+			}
+			tokenFile := cfg.ProxyFile("admin.token")
+			if err := ioutil.WriteFile(tokenFile, []byte(secret+"\n"), 0600); err != nil {
+				logger.Fatalf("Failed to write admin token: %s", err)
+			}
+			logger.Infof("Created admin token in %s", tokenFile)
+		}
+		authenticator.Enable(store)
+		logger.Info("Token authentication enabled")
+	}
+
+	networks, err := auth.ParseNetworks(cfg.LauncherNetworks)
+	if err != nil {
+		logger.Fatalf("Failed to parse --launcher-network: %s", err)
+	}
+	authenticator.TrustNetworks(networks)
+
+	authenticator.ConfigureRouter(router)
+	authenticator.ConfigureSpec(spec)
+}
+
+func initAudit() {
+	err := auditLogger.Open(cfg.AuditLog, audit.DefaultMaxSize, audit.DefaultMaxBackups)
+	if err != nil {
+		logger.Fatalf("Failed to open audit log: %s", err)
+	}
+	auditLogger.ConfigureRouter(router)
+	auditLogger.ConfigureSpec(spec)
+}
+
+func initLimiter() {
+	for _, value := range cfg.RateLimits {
+		if err := rateLimiter.ApplyRule(value); err != nil {
+			logger.Fatalf("Failed to parse --rate-limit: %s", err)
+		}
+	}
+	rateLimiter.ConfigureRouter(router)
+	rateLimiter.ConfigureSpec(spec)
+}
 
+func initCertManager() *certs.Manager {
+	options := certs.Options{
+		CertFile: cfg.ProxyFile("tls.crt"),
+		KeyFile:  cfg.ProxyFile("tls.key"),
+		Hosts:    cfg.TlsHosts,
+	}
+	if cfg.TlsCert != "" || cfg.TlsKey != "" {
+		if cfg.TlsCert == "" || cfg.TlsKey == "" {
+			logger.Fatal("Both --tls-cert and --tls-key are required to use your own certificate")
+		}
+		options.CertFile = cfg.TlsCert
+		options.KeyFile = cfg.TlsKey
+		options.UserProvided = true
+	}
+	manager, err := certs.NewManager(options)
+	if err != nil {
+		logger.Fatalf("Failed to initialize TLS certificate: %s", err)
+	}
 	manager.ConfigureRouter(router)
+	manager.ConfigureSpec(spec)
+	return manager
 }
 
-func generateTlsCertificate() error {
-	c := exec.Command("openssl", "req", "-newkey", "rsa:2048", "-nodes", "-keyout", "/root/.proxy/tls.key",
-		"-x509", "-days", "1095", "-subj", "/CN=localhost", "-out", "/root/.proxy/tls.crt")
-	fmt.Printf("%s\n", c.String())
-	c.Stdout = os.Stdout
-	c.Stderr = os.Stderr
-	return c.Run()
+func initClientCA() *certs.CA {
+	dir := cfg.ProxyFile("ca")
+	ca, err := certs.NewCA(dir)
+	if err != nil {
+		logger.Fatalf("Failed to initialize client CA: %s", err)
+	}
+	if len(ca.List()) == 0 {
+		// bootstrap an admin certificate so that the operator can issue other certificates
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			logger.Fatalf("Failed to generate password: %s", err)
+		}
+		password := hex.EncodeToString(buf)
+		_, bundle, err := ca.Issue("admin", string(auth.RoleAdmin), 0, password)
+		if err != nil {
+			logger.Fatalf("Failed to issue admin certificate: %s", err)
+		}
+		bundleFile := filepath.Join(dir, "admin.p12")
+		if err := ioutil.WriteFile(bundleFile, bundle, 0600); err != nil {
+			logger.Fatalf("Failed to write admin certificate: %s", err)
+		}
+		if err := ioutil.WriteFile(bundleFile+".password", []byte(password+"\n"), 0600); err != nil {
+			logger.Fatalf("Failed to write admin certificate password: %s", err)
+		}
+		logger.Infof("Created admin client certificate in %s", bundleFile)
+	}
+	return ca
 }
 
-func serve() error {
-	var err error
+func initTls() {
+	if !cfg.Tls {
+		return
+	}
+	manager := initCertManager()
+	tlsConfig = manager.TLSConfig()
+	if cfg.Mtls {
+		ca := initClientCA()
+		clientCA = ca
+		tlsConfig = ca.ServerTLSConfig(tlsConfig)
+		authenticator.EnableClientCerts(ca.Check)
+		ca.ConfigureRouter(router, func(role string) error {
+			_, err := auth.ParseRole(role)
+			return err
+		})
+		ca.ConfigureSpec(spec)
+		logger.Info("Mutual TLS enabled")
+	}
+}
 
-	addr := fmt.Sprintf(":%d", port)
-	logger.Infof("Serving at %s", addr)
+func initDoctor() {
+	d := doctor.New(cfg, nil)
+	d.ConfigureRouter(router)
+	d.ConfigureSpec(spec)
+}
 
-	if tls {
-		certFile := filepath.Join(homedir.Get(), ".proxy", "tls.crt")
-		keyFile := filepath.Join(homedir.Get(), ".proxy", "tls.key")
-		if !utils.FileExists(certFile) || !utils.FileExists(keyFile) {
-			//openssl req -newkey rsa:2048 -nodes -keyout /root/.proxy/tls.key -x509 -days 1095 -subj '/CN=localhost' -out /root/.proxy/tls.crt
-			if err := generateTlsCertificate(); err != nil {
-				return err
-			}
+// initGateway serves the gRPC gateway only when the callers are authenticated: without --auth or --mtls every call
+// would run as admin, including the wallet methods (SendCoins, CreateReverseSwap...)
+func initGateway() {
+	grpcGateway = gateway.New(manager.GetService, authenticator, rateLimiter, auditLogger)
+	if !cfg.Auth && !cfg.Mtls {
+		if cfg.GrpcPort != 0 {
+			logger.Fatal("The gRPC gateway (--grpc-port) requires --auth or --mtls")
 		}
-		err = http.ListenAndServeTLS(addr, certFile, keyFile, router)
-	} else {
-		err = http.ListenAndServe(addr, router)
+		logger.Info("gRPC-Web disabled (requires --auth or --mtls)")
+		return
 	}
-	if err != nil {
-		return err
+	if clientCA != nil {
+		grpcGateway.EnableClientCerts(clientCA.Check)
 	}
-	return nil
+	grpcGateway.ConfigureRouter(router)
+	grpcGateway.ConfigureSpec(spec)
 }
 
-func main() {
-	var err error
+// initOpenApi serves the spec of every route registered so far, so it goes last. main_test.go checks that every /api
+// route has a spec entry.
+func initOpenApi() {
+	spec.ConfigureRouter(router)
 
-	network = os.Getenv("NETWORK")
+	policy := authenticator.GetPolicy()
+	spec.Walk(func(method string, route string, op *openapi.Operation) {
+		op.RequireScope(string(policy.ScopeOf(method, route)))
+	})
 
-	cmd := &cobra.Command{
-		Use: "proxy",
-		Short: "The API gateway of opendexd-docker",
+}
+
+// sioConns returns every Socket.IO connection once (a connection can be in several rooms)
+func sioConns() []socketio.Conn {
+	var result []socketio.Conn
+	seen := map[string]bool{}
+	for _, room := range sioServer.Rooms("/") {
+		sioServer.ForEach("/", room, func(s socketio.Conn) {
+			if !seen[s.ID()] {
+				seen[s.ID()] = true
+				result = append(result, s)
+			}
+		})
 	}
-	cmd.PersistentFlags().Uint16VarP(&port, "port", "p", 8080, "The port to listen")
-	cmd.PersistentFlags().BoolVar(&tls, "tls", false, "Enable TLS support")
-	err = cmd.Execute()
-	if err != nil {
-		logger.Fatalf("Failed to parse command-line options: %s", err)
+	return result
+}
+
+// shutdown stops accepting new connections, tells the streaming clients to go away and waits for the in-flight
+// requests to finish until cfg.ShutdownTimeout. The services are closed afterwards.
+func shutdown(server *http.Server, cancelStreams context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Socket.IO and launcher connections are hijacked or long polling so they are not tracked by Shutdown
+	conns := sioConns()
+	for _, s := range conns {
+		s.Emit("shutdown")
+	}
+	manager.LauncherAgent.Close()
+	killConsoles()
+	// ends the streaming responses which follow their request context (e.g. subscribeorders)
+	cancelStreams()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Shutdown(ctx)
+	}()
+
+	// give the "shutdown" events a moment to be flushed before closing the Socket.IO connections
+	time.Sleep(sioNotifyDelay)
+	for _, s := range conns {
+		_ = s.Close()
+	}
+	if err := sioServer.Close(); err != nil {
+		logger.Errorf("Failed to close socket.io server: %s", err)
+	}
+	launcher.CloseLaunchers()
+
+	grpcGateway.Stop(ctx)
+	if err := <-done; err != nil {
+		logger.Warnf("Failed to wait for in-flight requests: %s", err)
+		_ = server.Close()
 	}
 
+	if err := manager.Close(); err != nil {
+		logger.Errorf("Failed to close service manager: %s", err)
+	}
+	if err := auditLogger.Close(); err != nil {
+		logger.Errorf("Failed to close audit log: %s", err)
+	}
+}
+
+func serve() error {
+	addr := fmt.Sprintf(":%d", cfg.Port)
+	logger.Infof("Serving at %s", addr)
+
+	baseCtx, cancelStreams := context.WithCancel(context.Background())
+	defer cancelStreams()
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: router,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		if tlsConfig != nil {
+			server.TLSConfig = tlsConfig
+			// the certificate comes from TLSConfig.GetCertificate so that renewals don't need a restart
+			errs <- server.ListenAndServeTLS("", "")
+		} else {
+			errs <- server.ListenAndServe()
+		}
+	}()
+
+	if cfg.GrpcPort != 0 {
+		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GrpcPort))
+		if err != nil {
+			return err
+		}
+		logger.Infof("Serving gRPC at %s", lis.Addr())
+		go func() {
+			errs <- grpcGateway.Serve(lis, tlsConfig)
+		}()
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case err := <-errs:
+		return err
+	case sig := <-signals:
+		logger.Infof("Received %s, shutting down (timeout %s)", sig, cfg.ShutdownTimeout)
+	}
+
+	shutdown(server, cancelStreams)
+
+	logger.Info("Shutdown complete")
+	return nil
+}
+
+// setup creates the router with every route and the services behind them
+func setup() {
+	router = initRouter()
+
+	// the client certificate check must be registered before any route
+	initTls()
+	initAuth()
+	initAudit()
+	initLimiter()
 	initSioServer()
 	initLauncherWs()
 	initServiceManager()
+	initDoctor()
+	initGateway()
+	initOpenApi()
+}
+
+func run() {
+	logger.Infof("Network %s (network directory %s, proxy directory %s)", cfg.Network, cfg.NetworkDir, cfg.ProxyDir)
+
+	setup()
 
-	err = serve()
+	err := serve()
 	if err != nil {
 		logger.Fatalf("Failed to serve: %s", err)
 	}
 }
+
+func main() {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "The API gateway of opendexd-docker",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfg.Load(cmd.Flags()); err != nil {
+				return err
+			}
+			run()
+			return nil
+		},
+	}
+	cfg.BindFlags(cmd.PersistentFlags())
+	cmd.AddCommand(newStatusCommand(), newLogsCommand(), newOrdersCommand(), newUnlockCommand(), newTokenCommand(), newDoctorCommand())
+	err := cmd.Execute()
+	if err != nil {
+		// cobra has printed the error
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"github.com/spf13/pflag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeServicesConfig creates the config.json of a testnet setup with every service
+func writeServicesConfig(t *testing.T, file string) {
+	grpc := func(port int) map[string]interface{} {
+		return map[string]interface{}{"type": "gRPC", "host": "127.0.0.1", "port": port, "tlsCert": "/x", "macaroon": "/y"}
+	}
+	jsonRpc := func(port int) map[string]interface{} {
+		return map[string]interface{}{"type": "JSON-RPC", "host": "127.0.0.1", "port": port, "username": "xu", "password": "xu"}
+	}
+	services := []map[string]interface{}{
+		{"name": "bitcoind", "rpc": jsonRpc(18332)},
+		{"name": "litecoind", "rpc": jsonRpc(19332)},
+		{"name": "geth", "rpc": jsonRpc(8545)},
+		{"name": "lndbtc", "rpc": grpc(10009)},
+		{"name": "lndltc", "rpc": grpc(10010)},
+		{"name": "connext", "rpc": map[string]interface{}{"type": "HTTP", "host": "127.0.0.1", "port": 8000}},
+		{"name": "opendexd", "rpc": grpc(28886)},
+		{"name": "arby", "rpc": map[string]interface{}{}},
+		{"name": "boltz", "rpc": map[string]interface{}{"bitcoin": grpc(9002), "litecoin": grpc(9102)}},
+		{"name": "webui", "rpc": map[string]interface{}{}},
+	}
+	for _, s := range services {
+		s["disabled"] = false
+	}
+	data, err := json.Marshal(map[string]interface{}{"services": services})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpecCoversRoutes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// every optional feature is enabled so that all the routes are registered
+	fs := pflag.NewFlagSet("proxy", pflag.ContinueOnError)
+	cfg.BindFlags(fs)
+	err = fs.Parse([]string{
+		"--network", "testnet",
+		"--network-dir", dir,
+		"--proxy-dir", filepath.Join(dir, "proxy"),
+		"--grpc-port", "28080",
+		"--tls", "--mtls", "--auth",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Load(fs); err != nil {
+		t.Fatal(err)
+	}
+	writeServicesConfig(t, cfg.ServicesConfig)
+
+	setup()
+	defer manager.LauncherAgent.Close()
+
+	if missing := spec.Missing(router.Routes()); len(missing) > 0 {
+		t.Errorf("routes missing in the OpenAPI spec:")
+		for _, route := range missing {
+			t.Errorf("  %s", route)
+		}
+	}
+}
@@ -26,6 +26,12 @@ func NewSioServer(network string) (*socketio.Server, error) {
 	}
 	server.OnConnect("/", func(s socketio.Conn) error {
 		logger.Debugf("[SocketIO/%s] CONNECT: RemoteAddr=%v", s.ID(), s.RemoteAddr())
+		u := s.URL()
+		identity, err := authenticator.Authenticate(&http.Request{Header: s.RemoteHeader(), URL: &u})
+		if err != nil {
+			return err
+		}
+		s.SetContext(identity)
 		t := s.RemoteHeader().Get("X-Type")
 		if t != "" {
 			logger.Debugf("[SocketIO/%s] Type=%s", s.ID(), t)
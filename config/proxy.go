@@ -0,0 +1,330 @@
+package config
+
+import (
+	"fmt"
+	"github.com/docker/docker/pkg/homedir"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// ContainerNetworkDir is where opendex-docker mounts the network directory into the proxy container. Paths in
+	// config.json are relative to the container.
+	ContainerNetworkDir = "/root/network"
+
+	envPrefix = "PROXY_"
+)
+
+// Config holds the options of the proxy. Every option can be set (from highest to lowest precedence) by a
+// command-line flag, an environment variable (PROXY_ + the flag name in upper snake case, e.g. PROXY_NETWORK_DIR) or
+// a key with the flag name in the YAML or TOML (.toml extension) file given by --config. The environment variable of
+// an option which may be repeated on the command-line (--rate-limit, --jsonrpc-allow) separates the values by
+// semicolons.
+type Config struct {
+	File string
+
+	Network string
+	Port    uint16
+	// GrpcPort serves the gRPC gateway. Zero disables it.
+	GrpcPort uint16
+
+	// NetworkDir is the opendex-docker network directory (e.g. ~/.opendex-docker/testnet on the host)
+	NetworkDir string
+	// DataDir defaults to NetworkDir/data
+	DataDir string
+	// ServicesConfig defaults to DataDir/config.json
+	ServicesConfig string
+	// LauncherLog defaults to NetworkDir/logs/<network>.log
+	LauncherLog string
+	// ProxyDir keeps the state of the proxy (TLS certificates, tokens, audit log...)
+	ProxyDir string
+	UiDir    string
+
+	// Backends overrides the RPC addresses of config.json, e.g. opendexd=localhost:28886 or boltz.bitcoin=localhost:9002
+	Backends map[string]string
+	// JsonRpcAllow replaces the allowlists of the JSON-RPC passthrough, e.g. bitcoind=getblock,getblockhash
+	JsonRpcAllow []string
+	// JsonRpcMethods is parsed from JsonRpcAllow by Load
+	JsonRpcMethods map[string][]string
+	// EthFailover switches connext to the best light provider when the current one fails
+	EthFailover bool
+	// EthTokens are ERC20 contracts (by symbol) whose balances /api/v1/geth/balances returns besides the currencies of
+	// opendexd
+	EthTokens map[string]string
+
+	Tls      bool
+	TlsCert  string
+	TlsKey   string
+	TlsHosts []string
+	Mtls     bool
+
+	Auth       bool
+	AuditLog   string
+	RateLimits []string
+	// LauncherNetworks may attach to /launcher without a token besides the loopback, e.g. the address of the launcher
+	// container
+	LauncherNetworks []string
+
+	// ShutdownTimeout is how long in-flight requests may take to finish after SIGTERM/SIGINT
+	ShutdownTimeout time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Network:    "",
+		Port:       8080,
+		NetworkDir: ContainerNetworkDir,
+		ProxyDir:   filepath.Join(homedir.Get(), ".proxy"),
+		UiDir:      "/ui",
+		Backends:   map[string]string{},
+		EthTokens:  map[string]string{},
+
+		ShutdownTimeout: 15 * time.Second,
+	}
+}
+
+// BindFlags registers the command-line flags of the options
+func (t *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&t.File, "config", "c", t.File, "The YAML or TOML (.toml) configuration file")
+	fs.StringVar(&t.Network, "network", t.Network, "The network: mainnet, testnet or simnet (falls back to $NETWORK)")
+	fs.Uint16VarP(&t.Port, "port", "p", t.Port, "The port to listen")
+	fs.Uint16Var(&t.GrpcPort, "grpc-port", t.GrpcPort, "The port of the gRPC gateway (0 disables it)")
+
+	fs.StringVar(&t.NetworkDir, "network-dir", t.NetworkDir, "The opendex-docker network directory")
+	fs.StringVar(&t.DataDir, "data-dir", t.DataDir, "The data directory of the services (default <network-dir>/data)")
+	fs.StringVar(&t.ServicesConfig, "services-config", t.ServicesConfig, "The services configuration (default <data-dir>/config.json)")
+	fs.StringVar(&t.LauncherLog, "launcher-log", t.LauncherLog, "The launcher log file (default <network-dir>/logs/<network>.log)")
+	fs.StringVar(&t.ProxyDir, "proxy-dir", t.ProxyDir, "The state directory of the proxy")
+	fs.StringVar(&t.UiDir, "ui-dir", t.UiDir, "The web UI directory")
+	fs.StringToStringVar(&t.Backends, "backend", t.Backends, "Override the RPC address of a service, e.g. opendexd=localhost:28886")
+	fs.StringArrayVar(&t.JsonRpcAllow, "jsonrpc-allow", t.JsonRpcAllow, "Replace the JSON-RPC methods forwarded to bitcoind, litecoind or geth, e.g. \"bitcoind=getblock,sendrawtransaction\" (\"*\" allows all)")
+	fs.StringToStringVar(&t.EthTokens, "eth-token", t.EthTokens, "Add the balance of an ERC20 contract to /api/v1/geth/balances, e.g. USDT=0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	fs.BoolVar(&t.EthFailover, "eth-failover", t.EthFailover, "Switch connext to the healthiest Ethereum light provider (through the launcher) when the current one fails")
+
+	fs.BoolVar(&t.Tls, "tls", t.Tls, "Enable TLS support")
+	fs.StringVar(&t.TlsCert, "tls-cert", t.TlsCert, "Use your own TLS certificate instead of the generated one")
+	fs.StringVar(&t.TlsKey, "tls-key", t.TlsKey, "The key of --tls-cert")
+	fs.StringSliceVar(&t.TlsHosts, "tls-san", t.TlsHosts, "Extra hostnames, IP or .onion addresses of the generated TLS certificate")
+	fs.BoolVar(&t.Mtls, "mtls", t.Mtls, "Require TLS client certificates issued by the proxy (implies --tls)")
+
+	fs.BoolVar(&t.Auth, "auth", t.Auth, "Require API tokens")
+	fs.StringSliceVar(&t.LauncherNetworks, "launcher-network", t.LauncherNetworks, "Let the launcher attach to /launcher without a token from these networks besides the loopback, e.g. 172.18.0.5/32 for the launcher container")
+	fs.StringVar(&t.AuditLog, "audit-log", t.AuditLog, "The audit log file (default <proxy-dir>/audit.log)")
+	fs.StringArrayVar(&t.RateLimits, "rate-limit", t.RateLimits, "Override a rate limit rule, e.g. \"POST /api/v1/opendexd/unlock:rate=0.1,burst=3,failures=3,lockout=1m,maxLockout=1h\" (use \"*\" for the default rule)")
+
+	fs.DurationVar(&t.ShutdownTimeout, "shutdown-timeout", t.ShutdownTimeout, "How long to wait for in-flight requests when shutting down")
+}
+
+func envName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// isArray checks if a flag keeps every value whole, e.g. the rate limit rules which contain commas themselves
+func isArray(f *pflag.Flag) bool {
+	return f.Value.Type() == "stringArray"
+}
+
+func setFlag(f *pflag.Flag, values []string) error {
+	if s, ok := f.Value.(pflag.SliceValue); ok {
+		return s.Replace(values)
+	}
+	return f.Value.Set(strings.Join(values, ","))
+}
+
+// envValues splits the value of an environment variable: the values of a string array are separated by semicolons,
+// the ones of the other lists by commas and any other option takes the value as a whole
+func envValues(f *pflag.Flag, value string) []string {
+	if isArray(f) {
+		return strings.Split(value, ";")
+	}
+	if _, ok := f.Value.(pflag.SliceValue); ok {
+		return strings.Split(value, ",")
+	}
+	return []string{value}
+}
+
+func fileValues(f *pflag.Flag, value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		var result []string
+		for _, item := range v {
+			result = append(result, fmt.Sprint(item))
+		}
+		return result
+	case map[interface{}]interface{}:
+		var result []string
+		for key, item := range v {
+			result = append(result, fmt.Sprintf("%v=%v", key, item))
+		}
+		if isArray(f) {
+			// e.g. jsonrpc-allow as a map of the methods by service
+			sort.Strings(result)
+			return result
+		}
+		return []string{strings.Join(result, ",")}
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// Load applies the configuration file and the environment variables to the options which were not set on the
+// command-line, then fills in the derived paths. It must be called after the flags have been parsed.
+func (t *Config) Load(fs *pflag.FlagSet) error {
+	if !fs.Changed("config") {
+		if value, ok := os.LookupEnv(envName("config")); ok {
+			t.File = value
+		}
+	}
+
+	if t.File != "" {
+		data, err := ioutil.ReadFile(t.File)
+		if err != nil {
+			return err
+		}
+		var values map[string]interface{}
+		if strings.EqualFold(filepath.Ext(t.File), ".toml") {
+			values, err = decodeToml(data)
+		} else {
+			err = yaml.Unmarshal(data, &values)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid config file %s: %s", t.File, err)
+		}
+		for key, value := range values {
+			f := fs.Lookup(key)
+			if f == nil || key == "config" {
+				return fmt.Errorf("invalid config file %s: unknown option %s", t.File, key)
+			}
+			if f.Changed {
+				continue
+			}
+			if err := setFlag(f, fileValues(f, value)); err != nil {
+				return fmt.Errorf("invalid config file %s: %s: %s", t.File, key, err)
+			}
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Changed || err != nil {
+			return
+		}
+		if value, ok := os.LookupEnv(envName(f.Name)); ok {
+			if e := setFlag(f, envValues(f, value)); e != nil {
+				err = fmt.Errorf("invalid %s: %s", envName(f.Name), e)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if t.Network == "" {
+		// opendex-docker passes the network to the proxy container this way
+		t.Network = os.Getenv("NETWORK")
+	}
+
+	if t.DataDir == "" {
+		t.DataDir = filepath.Join(t.NetworkDir, "data")
+	}
+	if t.ServicesConfig == "" {
+		t.ServicesConfig = filepath.Join(t.DataDir, "config.json")
+	}
+	if t.LauncherLog == "" {
+		t.LauncherLog = filepath.Join(t.NetworkDir, "logs", fmt.Sprintf("%s.log", t.Network))
+	}
+	if t.AuditLog == "" {
+		t.AuditLog = filepath.Join(t.ProxyDir, "audit.log")
+	}
+	if t.Mtls {
+		t.Tls = true
+	}
+
+	return t.parseJsonRpcAllow()
+}
+
+// parseJsonRpcAllow groups the methods by service
+func (t *Config) parseJsonRpcAllow() error {
+	t.JsonRpcMethods = map[string][]string{}
+	for _, value := range t.JsonRpcAllow {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid --jsonrpc-allow %q: must be <service>=<method>,<method>...", value)
+		}
+		service := strings.TrimSpace(parts[0])
+		if service != "bitcoind" && service != "litecoind" && service != "geth" {
+			return fmt.Errorf("invalid --jsonrpc-allow %q: the service must be bitcoind, litecoind or geth", value)
+		}
+		methods := []string{}
+		for _, method := range strings.Split(parts[1], ",") {
+			if method = strings.TrimSpace(method); method != "" {
+				methods = append(methods, method)
+			}
+		}
+		t.JsonRpcMethods[service] = methods
+	}
+	return nil
+}
+
+// ResolvePath maps a path inside the proxy container (as found in config.json) to the configured network directory
+func (t *Config) ResolvePath(path string) string {
+	if path == ContainerNetworkDir || strings.HasPrefix(path, ContainerNetworkDir+"/") {
+		return filepath.Join(t.NetworkDir, strings.TrimPrefix(path, ContainerNetworkDir))
+	}
+	return path
+}
+
+// ServiceDataDir is the data directory of a service, e.g. DataDir/lndbtc
+func (t *Config) ServiceDataDir(service string) string {
+	return filepath.Join(t.DataDir, service)
+}
+
+// PasswordUnsetFile is created by the launcher until the user sets the opendexd password
+func (t *Config) PasswordUnsetFile() string {
+	return filepath.Join(t.NetworkDir, ".password-unset")
+}
+
+func (t *Config) ProxyFile(name string) string {
+	return filepath.Join(t.ProxyDir, name)
+}
+
+// ContainerName is the name of a service container created by opendex-docker, e.g. testnet_opendexd_1
+func (t *Config) ContainerName(service string) string {
+	return fmt.Sprintf("%s_%s_1", t.Network, service)
+}
+
+// ResolveRpcConfig maps the container paths of config.json to the network directory and applies the backend
+// address overrides (e.g. "opendexd=localhost:28886" or "boltz.bitcoin=localhost:9002")
+func (t *Config) ResolveRpcConfig(name string, rpc RpcConfig) error {
+	for key, value := range rpc {
+		switch v := value.(type) {
+		case string:
+			rpc[key] = t.ResolvePath(v)
+		case map[string]interface{}:
+			if err := t.ResolveRpcConfig(name+"."+key, v); err != nil {
+				return err
+			}
+		}
+	}
+	if addr, ok := t.Backends[name]; ok {
+		host, portStr, err := net.SplitHostPort(addr)
+		if err != nil {
+			return fmt.Errorf("invalid backend address of %s: %s", name, err)
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid backend port of %s: %s", name, err)
+		}
+		rpc["host"] = host
+		rpc["port"] = float64(port)
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// decodeToml decodes the subset of TOML used by configuration files: comments, bare, quoted and dotted keys,
+// [tables], basic and literal strings, integers, floats, booleans, arrays and inline tables. Multi-line strings,
+// dates and arrays of tables are rejected. Tables are decoded like YAML mappings (map[interface{}]interface{}) and
+// arrays to []interface{} so that the values are applied the same way as the ones of a YAML file.
+func decodeToml(data []byte) (map[string]interface{}, error) {
+	p := &tomlParser{data: string(data), line: 1}
+	root, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %s", p.line, err)
+	}
+	result := map[string]interface{}{}
+	for key, value := range root {
+		result[key.(string)] = value
+	}
+	return result, nil
+}
+
+type tomlParser struct {
+	data string
+	pos  int
+	line int
+}
+
+func (t *tomlParser) eof() bool {
+	return t.pos >= len(t.data)
+}
+
+func (t *tomlParser) peek() byte {
+	if t.eof() {
+		return 0
+	}
+	return t.data[t.pos]
+}
+
+func (t *tomlParser) hasPrefix(s string) bool {
+	return strings.HasPrefix(t.data[t.pos:], s)
+}
+
+// skipSpaces skips the spaces and tabs of the current line
+func (t *tomlParser) skipSpaces() {
+	for !t.eof() && (t.peek() == ' ' || t.peek() == '\t') {
+		t.pos++
+	}
+}
+
+// skipBlank skips the spaces, comments and newlines
+func (t *tomlParser) skipBlank() {
+	for !t.eof() {
+		switch t.peek() {
+		case ' ', '\t', '\r':
+			t.pos++
+		case '\n':
+			t.pos++
+			t.line++
+		case '#':
+			for !t.eof() && t.peek() != '\n' {
+				t.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// endOfLine expects nothing but a comment until the end of the line
+func (t *tomlParser) endOfLine() error {
+	t.skipSpaces()
+	if t.peek() == '#' {
+		for !t.eof() && t.peek() != '\n' {
+			t.pos++
+		}
+	}
+	if t.hasPrefix("\r\n") {
+		t.pos++
+	}
+	if t.eof() {
+		return nil
+	}
+	if t.peek() != '\n' {
+		return fmt.Errorf("unexpected %q after value", t.peek())
+	}
+	t.pos++
+	t.line++
+	return nil
+}
+
+func (t *tomlParser) parse() (map[interface{}]interface{}, error) {
+	root := map[interface{}]interface{}{}
+	current := root
+	for {
+		t.skipBlank()
+		if t.eof() {
+			return root, nil
+		}
+		if t.peek() == '[' {
+			if t.hasPrefix("[[") {
+				return nil, fmt.Errorf("arrays of tables are not supported")
+			}
+			t.pos++
+			path, err := t.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			if t.peek() != ']' {
+				return nil, fmt.Errorf("expected ] after table name")
+			}
+			t.pos++
+			if current, err = table(root, path); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := t.parseKeyValue(current); err != nil {
+				return nil, err
+			}
+		}
+		if err := t.endOfLine(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// table returns the table at the path, creating the missing ones
+func table(root map[interface{}]interface{}, path []string) (map[interface{}]interface{}, error) {
+	current := root
+	for _, key := range path {
+		value, ok := current[key]
+		if !ok {
+			next := map[interface{}]interface{}{}
+			current[key] = next
+			current = next
+			continue
+		}
+		next, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is not a table", strings.Join(path, "."))
+		}
+		current = next
+	}
+	return current, nil
+}
+
+func (t *tomlParser) parseKeyValue(current map[interface{}]interface{}) error {
+	path, err := t.parseKey()
+	if err != nil {
+		return err
+	}
+	if t.peek() != '=' {
+		return fmt.Errorf("expected = after key %s", strings.Join(path, "."))
+	}
+	t.pos++
+	value, err := t.parseValue()
+	if err != nil {
+		return err
+	}
+	parent, err := table(current, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	key := path[len(path)-1]
+	if _, ok := parent[key]; ok {
+		return fmt.Errorf("duplicate key %s", strings.Join(path, "."))
+	}
+	parent[key] = value
+	return nil
+}
+
+// parseKey parses a key like backend."boltz.bitcoin" and the spaces around it
+func (t *tomlParser) parseKey() ([]string, error) {
+	var path []string
+	for {
+		t.skipSpaces()
+		var part string
+		var err error
+		switch t.peek() {
+		case '"':
+			part, err = t.parseBasicString()
+		case '\'':
+			part, err = t.parseLiteralString()
+		default:
+			start := t.pos
+			for !t.eof() && isBareKeyChar(t.peek()) {
+				t.pos++
+			}
+			if t.pos == start {
+				return nil, fmt.Errorf("expected key, got %q", t.peek())
+			}
+			part = t.data[start:t.pos]
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, part)
+		t.skipSpaces()
+		if t.peek() != '.' {
+			return path, nil
+		}
+		t.pos++
+	}
+}
+
+func isBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (t *tomlParser) parseBasicString() (string, error) {
+	if t.hasPrefix(`"""`) {
+		return "", fmt.Errorf("multi-line strings are not supported")
+	}
+	start := t.pos
+	t.pos++
+	for !t.eof() && t.peek() != '"' && t.peek() != '\n' {
+		if t.peek() == '\\' {
+			t.pos++
+		}
+		t.pos++
+	}
+	if t.peek() != '"' {
+		return "", fmt.Errorf("unterminated string")
+	}
+	t.pos++
+	s, err := strconv.Unquote(t.data[start:t.pos])
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", t.data[start:t.pos])
+	}
+	return s, nil
+}
+
+func (t *tomlParser) parseLiteralString() (string, error) {
+	if t.hasPrefix("'''") {
+		return "", fmt.Errorf("multi-line strings are not supported")
+	}
+	t.pos++
+	start := t.pos
+	for !t.eof() && t.peek() != '\'' && t.peek() != '\n' {
+		t.pos++
+	}
+	if t.peek() != '\'' {
+		return "", fmt.Errorf("unterminated string")
+	}
+	s := t.data[start:t.pos]
+	t.pos++
+	return s, nil
+}
+
+func (t *tomlParser) parseValue() (interface{}, error) {
+	t.skipSpaces()
+	switch t.peek() {
+	case '"':
+		return t.parseBasicString()
+	case '\'':
+		return t.parseLiteralString()
+	case '[':
+		return t.parseArray()
+	case '{':
+		return t.parseInlineTable()
+	}
+
+	start := t.pos
+	for !t.eof() && !strings.ContainsRune(",]}# \t\r\n", rune(t.peek())) {
+		t.pos++
+	}
+	token := t.data[start:t.pos]
+	switch token {
+	case "":
+		return nil, fmt.Errorf("missing value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	number := strings.ReplaceAll(token, "_", "")
+	if i, err := strconv.ParseInt(number, 0, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(number, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("unsupported value %s", token)
+}
+
+func (t *tomlParser) parseArray() ([]interface{}, error) {
+	t.pos++
+	result := []interface{}{}
+	for {
+		t.skipBlank()
+		if t.peek() == ']' {
+			t.pos++
+			return result, nil
+		}
+		value, err := t.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+		t.skipBlank()
+		switch t.peek() {
+		case ',':
+			t.pos++
+		case ']':
+			t.pos++
+			return result, nil
+		default:
+			return nil, fmt.Errorf("expected , or ] in array")
+		}
+	}
+}
+
+func (t *tomlParser) parseInlineTable() (map[interface{}]interface{}, error) {
+	t.pos++
+	result := map[interface{}]interface{}{}
+	t.skipSpaces()
+	if t.peek() == '}' {
+		t.pos++
+		return result, nil
+	}
+	for {
+		if err := t.parseKeyValue(result); err != nil {
+			return nil, err
+		}
+		t.skipSpaces()
+		switch t.peek() {
+		case ',':
+			t.pos++
+		case '}':
+			t.pos++
+			return result, nil
+		default:
+			return nil, fmt.Errorf("expected , or } in inline table")
+		}
+	}
+}
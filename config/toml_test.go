@@ -0,0 +1,91 @@
+package config
+
+import (
+	"github.com/spf13/pflag"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadToml(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "proxy.toml")
+	data := `# proxy.toml
+network = "testnet"   # trailing comment
+port = 18_889
+eth-failover = true
+jsonrpc-allow = [
+  "bitcoind=getblock,getblockhash",
+  'geth=*',
+]
+
+[backend]
+opendexd = "localhost:28886"
+"boltz.bitcoin" = "localhost:9002"
+`
+	if err := ioutil.WriteFile(file, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := DefaultConfig()
+	fs := pflag.NewFlagSet("proxy", pflag.ContinueOnError)
+	cfg.BindFlags(fs)
+	if err := fs.Parse([]string{"--config", file, "--port", "18890"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.Load(fs); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Network != "testnet" || !cfg.EthFailover {
+		t.Errorf("network=%s ethFailover=%v", cfg.Network, cfg.EthFailover)
+	}
+	if cfg.Port != 18890 {
+		t.Errorf("port=%d, the flag should take precedence", cfg.Port)
+	}
+	backends := map[string]string{"opendexd": "localhost:28886", "boltz.bitcoin": "localhost:9002"}
+	if !reflect.DeepEqual(cfg.Backends, backends) {
+		t.Errorf("backends=%v", cfg.Backends)
+	}
+	methods := map[string][]string{"bitcoind": {"getblock", "getblockhash"}, "geth": {"*"}}
+	if !reflect.DeepEqual(cfg.JsonRpcMethods, methods) {
+		t.Errorf("methods=%v", cfg.JsonRpcMethods)
+	}
+}
+
+func TestDecodeToml(t *testing.T) {
+	values, err := decodeToml([]byte(`
+a.b = { c = 1, "d.e" = [1.5, false] }
+s = "tab\tquote\" é"
+[x . 'y']
+z = -0x10
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"a": map[interface{}]interface{}{
+			"b": map[interface{}]interface{}{"c": int64(1), "d.e": []interface{}{1.5, false}},
+		},
+		"s": "tab\tquote\" é",
+		"x": map[interface{}]interface{}{"y": map[interface{}]interface{}{"z": int64(-16)}},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("values=%#v", values)
+	}
+
+	invalid := []string{
+		"a = 1\na = 2",
+		"a = 1 b = 2",
+		"[[servers]]\nname = 1",
+		"a = \"\"\"multi\"\"\"",
+		"a = 1979-05-27",
+		"a = [1, 2",
+		"a = 1\n[a]\nb = 2",
+	}
+	for _, data := range invalid {
+		if _, err := decodeToml([]byte(data)); err == nil {
+			t.Errorf("%q: expected an error", data)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package doctor
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"net/http"
+)
+
+func (t *Doctor) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/doctor", "Check the environment of the proxy").
+		Tag("doctor").
+		Describe("Validates config.json, the TLS certificates and macaroons of the services, dials their RPC ports and looks up their containers. Problems come with a suggested fix.").
+		Returns(Report{})
+}
+
+func (t *Doctor) ConfigureRouter(r *gin.Engine) {
+	api := r.Group("/api")
+	{
+		api.GET("/v1/doctor", func(c *gin.Context) {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), config.DefaultApiTimeout)
+			defer cancel()
+			c.JSON(http.StatusOK, t.Run(ctx))
+		})
+	}
+}
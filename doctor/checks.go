@@ -0,0 +1,325 @@
+package doctor
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	docker "github.com/docker/docker/client"
+	"github.com/opendexnetwork/opendex-docker-api/config"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// endpoint is an RPC endpoint in config.json: the rpc object of a service or a nested object like rpc.bitcoin of boltz
+type endpoint struct {
+	key      string
+	tlsCert  bool
+	macaroon bool
+	// cookie is an optional RPC cookie file
+	cookie bool
+}
+
+// schemas lists the RPC endpoints of every service supported by service.Manager. Every endpoint needs a host and a
+// port.
+var schemas = map[string][]endpoint{
+	"opendexd":  {{tlsCert: true}},
+	"lndbtc":    {{tlsCert: true, macaroon: true}},
+	"lndltc":    {{tlsCert: true, macaroon: true}},
+	"boltz":     {{key: "bitcoin", tlsCert: true, macaroon: true}, {key: "litecoin", tlsCert: true, macaroon: true}},
+	"bitcoind":  {{cookie: true}},
+	"litecoind": {{cookie: true}},
+	"geth":      {{}},
+	"connext":   {{}},
+	"arby":      nil,
+	"webui":     nil,
+}
+
+const (
+	certExpiryWarning = 30 * 24 * time.Hour
+	// macaroonV2 is the first byte of the binary format of lnd macaroons
+	macaroonV2 = 2
+)
+
+type serviceConfig struct {
+	Name     string
+	Disabled bool
+	Rpc      config.RpcConfig
+}
+
+func (t *Doctor) checkDocker(ctx context.Context) (*docker.Client, Check) {
+	client := t.dockerClient
+	if client == nil {
+		var err error
+		client, err = docker.NewEnvClient()
+		if err != nil {
+			return nil, failure("docker", fmt.Sprintf("Invalid Docker environment: %s", err), "Check $DOCKER_HOST and $DOCKER_CERT_PATH")
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, t.dialTimeout)
+	defer cancel()
+	ping, err := client.Ping(ctx)
+	if err != nil {
+		if client != t.dockerClient {
+			_ = client.Close()
+		}
+		return nil, failure("docker", fmt.Sprintf("Cannot connect to Docker: %s", err),
+			"Mount /var/run/docker.sock into the proxy container (or set $DOCKER_HOST) and make sure the proxy may access it")
+	}
+	return client, ok("docker", "Docker API %s", ping.APIVersion)
+}
+
+// loadServices validates config.json and returns the services which passed the validation with their RPC
+// configuration resolved like service.Manager does
+func (t *Doctor) loadServices() ([]serviceConfig, []Check) {
+	file := t.config.ServicesConfig
+	fix := fmt.Sprintf("Fix %s or restart opendex-docker to regenerate it", file)
+
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return nil, []Check{failure("config", fmt.Sprintf("%s does not exist", file),
+			"Start opendex-docker first (the launcher writes config.json) or point --network-dir, --data-dir or --services-config at it")}
+	} else if err != nil {
+		return nil, []Check{failure("config", fmt.Sprintf("Cannot read %s: %s", file, err), "Make sure the proxy may read it")}
+	}
+
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, []Check{failure("config", fmt.Sprintf("%s is not valid JSON: %s", file, err), fix)}
+	}
+	items, isArray := root["services"].([]interface{})
+	if !isArray {
+		return nil, []Check{failure("config", fmt.Sprintf("%s has no \"services\" array", file), fix)}
+	}
+
+	var services []serviceConfig
+	var checks []Check
+	seen := map[string]bool{}
+	for i, item := range items {
+		problems, s := validateService(item)
+		if len(problems) == 0 && seen[s.Name] {
+			problems = append(problems, "duplicate service")
+		}
+		for _, problem := range problems {
+			label := fmt.Sprintf("services[%d]", i)
+			if s.Name != "" {
+				label = fmt.Sprintf("%s (%s)", label, s.Name)
+			}
+			checks = append(checks, failure("config", fmt.Sprintf("%s: %s", label, problem), fix))
+		}
+		if len(problems) > 0 {
+			continue
+		}
+		seen[s.Name] = true
+		if err := t.config.ResolveRpcConfig(s.Name, s.Rpc); err != nil {
+			checks = append(checks, failure("config", err.Error(), "Use --backend <service>=<host>:<port>"))
+			continue
+		}
+		services = append(services, s)
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, ok("config", "%s has %d services", file, len(services)))
+	}
+	return services, checks
+}
+
+// validateService checks an entry of config.json against the types asserted by service.Manager and the schema of
+// its RPC endpoints
+func validateService(item interface{}) ([]string, serviceConfig) {
+	var s serviceConfig
+	x, isObject := item.(map[string]interface{})
+	if !isObject {
+		return []string{"must be an object"}, s
+	}
+	name, isString := x["name"].(string)
+	if !isString || name == "" {
+		return []string{"\"name\" must be a non-empty string"}, s
+	}
+	s.Name = name
+
+	schema, known := schemas[name]
+	if !known {
+		return []string{"unsupported service"}, s
+	}
+
+	var problems []string
+	disabled, isBool := x["disabled"].(bool)
+	if !isBool {
+		problems = append(problems, "\"disabled\" must be a boolean")
+	}
+	s.Disabled = disabled
+	if mode, exists := x["mode"]; exists && mode != nil {
+		if _, isString := mode.(string); !isString {
+			problems = append(problems, "\"mode\" must be a string")
+		}
+	}
+	rpc, isObject := x["rpc"].(map[string]interface{})
+	if !isObject {
+		return append(problems, "\"rpc\" must be an object"), s
+	}
+	s.Rpc = rpc
+
+	for _, e := range schema {
+		path := "rpc"
+		fields := rpc
+		if e.key != "" {
+			path = "rpc." + e.key
+			fields, isObject = rpc[e.key].(map[string]interface{})
+			if !isObject {
+				problems = append(problems, fmt.Sprintf("%q must be an object", path))
+				continue
+			}
+		}
+		if host, isString := fields["host"].(string); !isString || host == "" {
+			problems = append(problems, fmt.Sprintf("\"%s.host\" must be a non-empty string", path))
+		}
+		port, isNumber := fields["port"].(float64)
+		if !isNumber || port < 1 || port > math.MaxUint16 || port != math.Trunc(port) {
+			problems = append(problems, fmt.Sprintf("\"%s.port\" must be a number between 1 and 65535", path))
+		}
+		if e.tlsCert {
+			if value, isString := fields["tlsCert"].(string); !isString || value == "" {
+				problems = append(problems, fmt.Sprintf("\"%s.tlsCert\" must be a file path", path))
+			}
+		}
+		if e.macaroon {
+			if value, isString := fields["macaroon"].(string); !isString || value == "" {
+				problems = append(problems, fmt.Sprintf("\"%s.macaroon\" must be a file path", path))
+			}
+		}
+	}
+	return problems, s
+}
+
+func (t *Doctor) checkService(ctx context.Context, s serviceConfig, dockerClient *docker.Client) []Check {
+	if s.Disabled {
+		return []Check{skipped(s.Name, "Disabled in config.json")}
+	}
+
+	checks := []Check{t.checkContainer(ctx, s.Name, dockerClient)}
+	for _, e := range schemas[s.Name] {
+		name := s.Name
+		fields := s.Rpc
+		if e.key != "" {
+			name = s.Name + "." + e.key
+			fields = s.Rpc[e.key].(map[string]interface{})
+		}
+		if e.tlsCert {
+			checks = append(checks, t.checkCert(name+".tlsCert", fields["tlsCert"].(string), s.Name))
+		}
+		if e.macaroon {
+			checks = append(checks, t.checkMacaroon(name+".macaroon", fields["macaroon"].(string), s.Name))
+		}
+		if file, ok := fields["cookie"].(string); e.cookie && ok && file != "" {
+			checks = append(checks, t.checkCookie(name+".cookie", file, s.Name))
+		}
+		host := fields["host"].(string)
+		port := strconv.Itoa(int(fields["port"].(float64)))
+		checks = append(checks, t.checkDial(ctx, name+".dial", net.JoinHostPort(host, port), name))
+	}
+	return checks
+}
+
+func (t *Doctor) checkContainer(ctx context.Context, service string, dockerClient *docker.Client) Check {
+	name := service + ".container"
+	if dockerClient == nil {
+		return skipped(name, "Docker is unavailable")
+	}
+	containerName := t.config.ContainerName(service)
+	c, err := dockerClient.ContainerInspect(ctx, containerName)
+	if docker.IsErrNotFound(err) {
+		return failure(name, fmt.Sprintf("Container %s does not exist", containerName),
+			fmt.Sprintf("Check --network (containers are named <network>_<service>_1) or start %s with opendex-docker", service))
+	} else if err != nil {
+		return failure(name, fmt.Sprintf("Cannot inspect container %s: %s", containerName, err), "Check the Docker daemon")
+	}
+	if !c.State.Running {
+		return warning(name, fmt.Sprintf("Container %s is %s", containerName, c.State.Status),
+			fmt.Sprintf("Start it with opendex-docker and check its logs (proxy logs %s)", service))
+	}
+	return ok(name, "Container %s is running", containerName)
+}
+
+// readFile explains why a file of a service cannot be read
+func (t *Doctor) readFile(name string, file string, service string) ([]byte, *Check) {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		check := failure(name, fmt.Sprintf("%s does not exist", file),
+			fmt.Sprintf("Wait for %s to create it, or check that --network-dir (%s) is the opendex-docker network directory", service, t.config.NetworkDir))
+		return nil, &check
+	} else if err != nil {
+		check := failure(name, fmt.Sprintf("Cannot read %s: %s", file, err),
+			"Run the proxy as a user who may read it (or inside the proxy container)")
+		return nil, &check
+	}
+	return data, nil
+}
+
+func (t *Doctor) checkCert(name string, file string, service string) Check {
+	data, problem := t.readFile(name, file, service)
+	if problem != nil {
+		return *problem
+	}
+	regenerate := fmt.Sprintf("Delete %s and its key and restart %s to regenerate them", file, service)
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return failure(name, fmt.Sprintf("%s is not a PEM file", file), regenerate)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return failure(name, fmt.Sprintf("%s is not a valid certificate: %s", file, err), regenerate)
+	}
+	now := time.Now()
+	if now.After(cert.NotAfter) {
+		return failure(name, fmt.Sprintf("%s expired on %s", file, cert.NotAfter.Format(time.RFC3339)), regenerate)
+	}
+	if cert.NotAfter.Sub(now) < certExpiryWarning {
+		return warning(name, fmt.Sprintf("%s expires on %s", file, cert.NotAfter.Format(time.RFC3339)), regenerate)
+	}
+	return ok(name, "%s is valid until %s", file, cert.NotAfter.Format(time.RFC3339))
+}
+
+func (t *Doctor) checkMacaroon(name string, file string, service string) Check {
+	data, problem := t.readFile(name, file, service)
+	if problem != nil {
+		return *problem
+	}
+	if len(data) == 0 {
+		return failure(name, fmt.Sprintf("%s is empty", file), fmt.Sprintf("Restart %s to regenerate it", service))
+	}
+	if data[0] != macaroonV2 {
+		return warning(name, fmt.Sprintf("%s is not a binary macaroon", file),
+			fmt.Sprintf("Check that it is the admin.macaroon of %s", service))
+	}
+	return ok(name, "%s", file)
+}
+
+func (t *Doctor) checkCookie(name string, file string, service string) Check {
+	data, problem := t.readFile(name, file, service)
+	if problem != nil {
+		return *problem
+	}
+	if !strings.Contains(string(data), ":") {
+		return failure(name, fmt.Sprintf("%s is not an RPC cookie", file),
+			fmt.Sprintf("Point \"cookie\" at the .cookie file in the data directory of %s", service))
+	}
+	return ok(name, "%s", file)
+}
+
+func (t *Doctor) checkDial(ctx context.Context, name string, addr string, service string) Check {
+	dialer := net.Dialer{Timeout: t.dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return failure(name, fmt.Sprintf("Cannot connect to %s: %s", addr, err),
+			fmt.Sprintf("Check that %s is running and listens on %s, or override the address with --backend %s=<host>:<port>", service, addr, service))
+	}
+	_ = conn.Close()
+	return ok(name, "%s is reachable", addr)
+}
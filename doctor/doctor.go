@@ -0,0 +1,126 @@
+// Package doctor checks the environment of the proxy (config.json, TLS certificates, macaroons, RPC ports and
+// Docker) and suggests fixes for the problems it finds
+package doctor
+
+import (
+	"context"
+	"fmt"
+	docker "github.com/docker/docker/client"
+	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultDialTimeout = 3 * time.Second
+)
+
+type Status string
+
+const (
+	StatusOk      Status = "ok"
+	StatusWarning Status = "warning"
+	StatusError   Status = "error"
+	// StatusSkipped checks depend on a failed check
+	StatusSkipped Status = "skipped"
+)
+
+type Check struct {
+	// Name identifies the check, e.g. "lndbtc.macaroon" or "boltz.bitcoin.dial"
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	// Fix is an actionable suggestion for warnings and errors
+	Fix string `json:"fix,omitempty"`
+}
+
+type Report struct {
+	Checks   []Check `json:"checks"`
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+}
+
+func (t *Report) add(checks ...Check) {
+	for _, check := range checks {
+		switch check.Status {
+		case StatusError:
+			t.Errors++
+		case StatusWarning:
+			t.Warnings++
+		}
+		t.Checks = append(t.Checks, check)
+	}
+}
+
+type Doctor struct {
+	config       *config.Config
+	dockerClient *docker.Client
+	dialTimeout  time.Duration
+	logger       *logrus.Entry
+}
+
+// New creates a doctor of the configuration. The Docker client may be nil and is then created from the environment
+// on every run.
+func New(cfg *config.Config, dockerClient *docker.Client) *Doctor {
+	return &Doctor{
+		config:       cfg,
+		dockerClient: dockerClient,
+		dialTimeout:  DefaultDialTimeout,
+		logger:       logrus.NewEntry(logrus.StandardLogger()).WithField("name", "Doctor"),
+	}
+}
+
+func ok(name string, format string, args ...interface{}) Check {
+	return Check{Name: name, Status: StatusOk, Message: fmt.Sprintf(format, args...)}
+}
+
+func warning(name string, message string, fix string) Check {
+	return Check{Name: name, Status: StatusWarning, Message: message, Fix: fix}
+}
+
+func failure(name string, message string, fix string) Check {
+	return Check{Name: name, Status: StatusError, Message: message, Fix: fix}
+}
+
+func skipped(name string, reason string) Check {
+	return Check{Name: name, Status: StatusSkipped, Message: reason}
+}
+
+// Run checks the network, Docker and config.json and then every enabled service concurrently. The checks are
+// reported in the order of config.json.
+func (t *Doctor) Run(ctx context.Context) *Report {
+	report := &Report{Checks: []Check{}}
+
+	if t.config.Network == "" {
+		report.add(failure("network", "The network is not set", "Pass --network mainnet, testnet or simnet (or set $NETWORK)"))
+	} else {
+		report.add(ok("network", "%s", t.config.Network))
+	}
+
+	dockerClient, check := t.checkDocker(ctx)
+	report.add(check)
+	if dockerClient != nil && dockerClient != t.dockerClient {
+		defer dockerClient.Close()
+	}
+
+	services, checks := t.loadServices()
+	report.add(checks...)
+
+	results := make([][]Check, len(services))
+	var wg sync.WaitGroup
+	for i, s := range services {
+		wg.Add(1)
+		go func(i int, s serviceConfig) {
+			defer wg.Done()
+			results[i] = t.checkService(ctx, s, dockerClient)
+		}(i, s)
+	}
+	wg.Wait()
+	for _, checks := range results {
+		report.add(checks...)
+	}
+
+	t.logger.Debugf("Finished with %d error(s) and %d warning(s)", report.Errors, report.Warnings)
+	return report
+}
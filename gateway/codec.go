@@ -0,0 +1,41 @@
+package gateway
+
+import (
+	"fmt"
+)
+
+// frame is a message forwarded as is
+type frame struct {
+	payload []byte
+}
+
+// codec passes the frames through. It is named "proto" so that the content type of the calls stays
+// application/grpc+proto.
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	f, ok := v.(*frame)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type: %T", v)
+	}
+	return f.payload, nil
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	f, ok := v.(*frame)
+	if !ok {
+		return fmt.Errorf("unexpected message type: %T", v)
+	}
+	// the buffer may be reused by the transport
+	f.payload = append(f.payload[:0], data...)
+	return nil
+}
+
+func (codec) Name() string {
+	return "proto"
+}
+
+// String implements grpc.Codec for grpc.CustomCodec
+func (codec) String() string {
+	return "proto"
+}
@@ -0,0 +1,244 @@
+// Package gateway forwards native gRPC and gRPC-Web calls of the opendexrpc, lnrpc and boltzrpc services to the
+// backends over the connections of the service manager, so that the TLS certificates and macaroons of config.json
+// are used. Messages are forwarded without being decoded.
+package gateway
+
+import (
+	"context"
+	"crypto/x509"
+	"github.com/opendexnetwork/opendex-docker-api/audit"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/limiter"
+	"github.com/opendexnetwork/opendex-docker-api/rpc"
+	"github.com/opendexnetwork/opendex-docker-api/service/core"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BackendHeader selects the backend of a package served by several services, e.g. "lndltc" or "boltz.litecoin"
+const BackendHeader = "x-backend"
+
+// packages maps the proto packages to the backends serving them. The first backend is the default one.
+var packages = map[string][]string{
+	"opendexrpc": {"opendexd"},
+	"lnrpc":      {"lndbtc", "lndltc"},
+	"boltzrpc":   {"boltz.bitcoin", "boltz.litecoin"},
+}
+
+// streamDesc fits every kind of method: unary calls are a stream with one message each way
+var streamDesc = &grpc.StreamDesc{
+	StreamName:    "gateway",
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+// Backend is implemented by the services with gRPC connections. The keys of GrpcConns are the part after the dot of
+// the backend name ("" for a single connection).
+type Backend interface {
+	GrpcConns() map[string]*rpc.GrpcConn
+}
+
+type Gateway struct {
+	services      func(name string) (core.Service, error)
+	authenticator *auth.Authenticator
+	limiter       *limiter.Limiter
+	auditLogger   *audit.Logger
+	checkCert     func(cert *x509.Certificate) error
+
+	server *grpc.Server
+	mutex  *sync.Mutex
+	// stopping ends the server streaming calls on shutdown
+	stopping chan struct{}
+	logger   *logrus.Entry
+}
+
+func New(services func(name string) (core.Service, error), authenticator *auth.Authenticator, limiter *limiter.Limiter, auditLogger *audit.Logger) *Gateway {
+	return &Gateway{
+		services:      services,
+		authenticator: authenticator,
+		limiter:       limiter,
+		auditLogger:   auditLogger,
+		stopping:      make(chan struct{}),
+		mutex:         &sync.Mutex{},
+		logger:        logrus.NewEntry(logrus.StandardLogger()).WithField("name", "gateway"),
+	}
+}
+
+// EnableClientCerts requires a client certificate accepted by check on the native gRPC server. gRPC-Web requests go
+// through the auth middleware instead.
+func (t *Gateway) EnableClientCerts(check func(cert *x509.Certificate) error) {
+	t.checkCert = check
+}
+
+type method struct {
+	// name is the full method name, e.g. /opendexrpc.Xud/GetInfo
+	name string
+	pkg  string
+	desc protoreflect.MethodDescriptor
+}
+
+func parseMethod(name string) (*method, error) {
+	s := strings.TrimPrefix(name, "/")
+	i := strings.LastIndex(s, "/")
+	if i < 0 {
+		return nil, status.Errorf(codes.Unimplemented, "malformed method name: %s", name)
+	}
+	d, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(s[:i]))
+	if err != nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown service: %s", s[:i])
+	}
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "unknown service: %s", s[:i])
+	}
+	pkg := string(sd.ParentFile().Package())
+	if _, ok := packages[pkg]; !ok {
+		return nil, status.Errorf(codes.Unimplemented, "service %s is not forwarded", s[:i])
+	}
+	md := sd.Methods().ByName(protoreflect.Name(s[i+1:]))
+	if md == nil {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method: %s", name)
+	}
+	return &method{name: "/" + s, pkg: pkg, desc: md}, nil
+}
+
+// connOf returns the connection to the backend (the default one of the package if empty)
+func (t *Gateway) connOf(pkg string, backend string) (*grpc.ClientConn, error) {
+	candidates := packages[pkg]
+	name := candidates[0]
+	if backend != "" {
+		name = ""
+		for _, candidate := range candidates {
+			if candidate == backend {
+				name = candidate
+			}
+		}
+		if name == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s %q for %s: must be one of %s", BackendHeader, backend, pkg, strings.Join(candidates, ", "))
+		}
+	}
+
+	serviceName, key := name, ""
+	if i := strings.Index(name, "."); i > 0 {
+		serviceName, key = name[:i], name[i+1:]
+	}
+	s, err := t.services(serviceName)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%s is not available", serviceName)
+	}
+	if s.IsDisabled() {
+		return nil, status.Errorf(codes.Unavailable, "%s is disabled", serviceName)
+	}
+	b, ok := s.(Backend)
+	if !ok {
+		return nil, status.Errorf(codes.Unavailable, "%s has no gRPC connection", serviceName)
+	}
+	c, ok := b.GrpcConns()[key]
+	if !ok || c == nil {
+		return nil, status.Errorf(codes.Unavailable, "%s has no gRPC connection", name)
+	}
+	conn := c.GetConn()
+	if conn == nil {
+		return nil, status.Errorf(codes.Unavailable, "%s is not connected", name)
+	}
+	return conn, nil
+}
+
+// call is a forwarded call which passed authentication, authorization and rate limiting
+type call struct {
+	method   *method
+	identity *auth.Identity
+	subjects []string
+	// rule is the rate limit rule of the password methods
+	rule  string
+	start time.Time
+}
+
+// begin checks the caller of a method. The errors are gRPC status errors; the call is returned with them once the
+// caller is known.
+func (t *Gateway) begin(name string, secret string, chains [][]*x509.Certificate, clientIp string) (*call, error) {
+	m, err := parseMethod(name)
+	if err != nil {
+		return nil, err
+	}
+	c := &call{
+		method:   m,
+		subjects: limiter.Subjects(clientIp, secret),
+		start:    time.Now(),
+	}
+
+	identity, err := t.authenticator.Resolve(secret, chains)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	c.identity = identity
+	scope := scopeOf(m.name)
+	if !identity.Role.Allows(scope) {
+		t.logger.Debugf("Denied %s for %s (role=%s, scope=%s)", m.name, identity.Name, identity.Role, scope)
+		return c, status.Errorf(codes.PermissionDenied, "permission denied: %s scope required", scope)
+	}
+
+	if route, ok := passwordRoutes[m.name]; ok {
+		rule, r := t.limiter.RuleOf(route)
+		if until := t.limiter.LockedUntil(rule, c.subjects...); !until.IsZero() {
+			return c, status.Errorf(codes.ResourceExhausted, "too many failed attempts, locked out until %s", until.UTC().Format(time.RFC3339))
+		}
+		if ok, wait := t.limiter.Allow(rule, r, c.subjects...); !ok {
+			return c, status.Errorf(codes.ResourceExhausted, "too many requests, retry in %s", wait.Round(time.Second))
+		}
+		c.rule = rule
+	}
+	return c, nil
+}
+
+// end accounts the outcome of a password method
+func (t *Gateway) end(c *call, err error) {
+	if c.rule == "" {
+		return
+	}
+	if limiter.IsPasswordError(err) {
+		_, r := t.limiter.RuleOf(c.rule)
+		t.limiter.Fail(c.rule, r, c.subjects...)
+	} else if err == nil {
+		t.limiter.Succeed(c.rule, c.subjects...)
+	}
+}
+
+// open opens a stream of the call to the backend
+func (t *Gateway) open(ctx context.Context, c *call, backend string) (grpc.ClientStream, error) {
+	conn, err := t.connOf(c.method.pkg, backend)
+	if err != nil {
+		return nil, err
+	}
+	if c.method.desc.IsStreamingServer() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		go func() {
+			select {
+			case <-t.stopping:
+			case <-ctx.Done():
+			}
+			cancel()
+		}()
+	}
+	stream, err := conn.NewStream(ctx, streamDesc, c.method.name, grpc.ForceCodec(codec{}))
+	if err != nil {
+		return nil, err
+	}
+	t.logger.Debugf("Forwarding %s for %s", c.method.name, c.identity.Name)
+	return stream, nil
+}
+
+func backendOf(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,242 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/opendexnetwork/opendex-docker-api/audit"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/limiter"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// dropped are the metadata keys which are not forwarded to the backends. The macaroon comes from config.json.
+var dropped = map[string]bool{
+	"authorization": true,
+	"macaroon":      true,
+	BackendHeader:   true,
+	"content-type":  true,
+	"user-agent":    true,
+	"te":            true,
+}
+
+func outgoing(md metadata.MD) metadata.MD {
+	result := metadata.MD{}
+	for k, v := range md {
+		if dropped[k] || strings.HasPrefix(k, ":") || strings.HasPrefix(k, "grpc-") {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+func bearerToken(values []string) string {
+	for _, value := range values {
+		if strings.HasPrefix(value, "Bearer ") {
+			return strings.TrimSpace(strings.TrimPrefix(value, "Bearer "))
+		}
+	}
+	return ""
+}
+
+// httpStatus maps gRPC codes to the HTTP statuses of the audit log and the gRPC-Web responses
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Canceled:
+		// nginx's "client closed request"
+		return 499
+	}
+	return http.StatusInternalServerError
+}
+
+// Serve serves native gRPC on the listener until Stop is called. The TLS config may be nil.
+func (t *Gateway) Serve(lis net.Listener, tlsConfig *tls.Config) error {
+	opts := []grpc.ServerOption{
+		grpc.CustomCodec(codec{}),
+		grpc.UnknownServiceHandler(t.handle),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server := grpc.NewServer(opts...)
+	t.mutex.Lock()
+	t.server = server
+	t.mutex.Unlock()
+	return server.Serve(lis)
+}
+
+// Stop ends the server streaming calls (e.g. the subscriptions) of both transports and waits for the other native
+// calls to finish until the context is done
+func (t *Gateway) Stop(ctx context.Context) {
+	close(t.stopping)
+	t.mutex.Lock()
+	server := t.server
+	t.mutex.Unlock()
+	if server == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+func (t *Gateway) handle(srv interface{}, stream grpc.ServerStream) error {
+	ctx := stream.Context()
+	start := time.Now()
+	name, _ := grpc.MethodFromServerStream(stream)
+	md, _ := metadata.FromIncomingContext(ctx)
+	secret := bearerToken(md.Get("authorization"))
+
+	var chains [][]*x509.Certificate
+	clientIp := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		clientIp = p.Addr.String()
+		if host, _, err := net.SplitHostPort(clientIp); err == nil {
+			clientIp = host
+		}
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			chains = info.State.VerifiedChains
+		}
+	}
+
+	// the gin middlewares of the REST routes
+	subjects := limiter.Subjects(clientIp, secret)
+	if until := t.limiter.LockedUntil(limiter.DefaultRuleName, subjects...); !until.IsZero() {
+		return status.Errorf(codes.ResourceExhausted, "too many failed attempts, locked out until %s", until.UTC().Format(time.RFC3339))
+	}
+	if t.checkCert != nil {
+		if len(chains) == 0 {
+			return status.Error(codes.Unauthenticated, "client certificate required")
+		}
+		if err := t.checkCert(chains[0][0]); err != nil {
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+	}
+
+	c, err := t.begin(name, secret, chains, clientIp)
+	if err == nil {
+		err = t.forward(ctx, c, md, stream)
+		t.end(c, err)
+	} else if status.Code(err) == codes.Unauthenticated {
+		_, rule := t.limiter.RuleOf(limiter.DefaultRuleName)
+		t.limiter.Fail(limiter.DefaultRuleName, rule, subjects...)
+	}
+
+	if scopeOf(name) != auth.ScopeRead {
+		t.audit(c, name, clientIp, backendOf(md.Get(BackendHeader)), start, err)
+	}
+	return err
+}
+
+func (t *Gateway) audit(c *call, name string, clientIp string, backend string, start time.Time, err error) {
+	record := audit.Record{
+		Time:     start.UTC(),
+		Caller:   "unknown",
+		ClientIp: clientIp,
+		Method:   "GRPC",
+		Route:    name,
+		Path:     name,
+		Status:   httpStatus(status.Code(err)),
+		Outcome:  "success",
+		Latency:  time.Since(start).Milliseconds(),
+	}
+	if backend != "" {
+		record.Params = map[string]interface{}{"backend": backend}
+	}
+	if c != nil && c.identity != nil {
+		record.Caller = c.identity.Name
+		record.TokenId = c.identity.TokenId
+		record.Role = string(c.identity.Role)
+	}
+	if err != nil {
+		record.Outcome = "failure"
+		record.Error = status.Convert(err).Message()
+	}
+	t.auditLogger.Log(record)
+}
+
+// forward pumps the messages of the call in both directions and returns the status of the backend
+func (t *Gateway) forward(ctx context.Context, c *call, md metadata.MD, server grpc.ServerStream) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	client, err := t.open(metadata.NewOutgoingContext(ctx, outgoing(md)), c, backendOf(md.Get(BackendHeader)))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			f := &frame{}
+			if err := server.RecvMsg(f); err != nil {
+				if err == io.EOF {
+					_ = client.CloseSend()
+				} else {
+					cancel()
+				}
+				return
+			}
+			if err := client.SendMsg(f); err != nil {
+				// the backend ended the call, RecvMsg below returns its status
+				return
+			}
+		}
+	}()
+
+	if header, err := client.Header(); err == nil && len(header) > 0 {
+		if err := server.SendHeader(header); err != nil {
+			return err
+		}
+	}
+	for {
+		f := &frame{}
+		if err := client.RecvMsg(f); err != nil {
+			server.SetTrailer(client.Trailer())
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := server.SendMsg(f); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package gateway
+
+import (
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/limiter"
+	"net/http"
+)
+
+// scopes lowers the read-only and trading methods from ScopeAdmin like the policy of the REST routes
+var scopes = map[string]auth.Scope{}
+
+func setScope(scope auth.Scope, service string, methods ...string) {
+	for _, m := range methods {
+		scopes["/"+service+"/"+m] = scope
+	}
+}
+
+func init() {
+	setScope(auth.ScopeRead, "opendexrpc.Xud", "GetBalance", "GetInfo", "GetNodeInfo", "ListCurrencies", "ListOrders",
+		"ListPairs", "ListPeers", "OrderBook", "SubscribeOrders", "SubscribeSwapFailures", "SubscribeSwaps",
+		"SubscribeSwapsAccepted", "TradeHistory", "TradingLimits")
+	setScope(auth.ScopeTrade, "opendexrpc.Xud", "PlaceOrder", "PlaceOrderSync", "RemoveOrder", "RemoveAllOrders")
+	setScope(auth.ScopeTrade, "opendexrpc.XudInit", "UnlockNode")
+
+	setScope(auth.ScopeRead, "lnrpc.Lightning", "ChannelBalance", "ClosedChannels", "DecodePayReq", "DescribeGraph",
+		"EstimateFee", "FeeReport", "ForwardingHistory", "GetChanInfo", "GetInfo", "GetNetworkInfo", "GetNodeInfo",
+		"GetNodeMetrics", "GetRecoveryInfo", "GetTransactions", "ListChannels", "ListInvoices", "ListPayments",
+		"ListPeers", "ListUnspent", "LookupInvoice", "PendingChannels", "QueryRoutes", "SubscribeChannelEvents",
+		"SubscribeChannelGraph", "SubscribeInvoices", "SubscribePeerEvents", "SubscribeTransactions", "VerifyMessage",
+		"WalletBalance")
+
+	setScope(auth.ScopeRead, "boltzrpc.Boltz", "GetInfo", "GetServiceInfo", "GetSwapInfo", "ListSwaps")
+	setScope(auth.ScopeTrade, "boltzrpc.Boltz", "Deposit")
+}
+
+// scopeOf returns the scope required by a full method name. Withdrawals, the mnemonic, channel and node management
+// and every method not listed above require ScopeAdmin.
+func scopeOf(method string) auth.Scope {
+	if scope, ok := scopes[method]; ok {
+		return scope
+	}
+	return auth.ScopeAdmin
+}
+
+// passwordRoutes shares the rate limit rules of the REST routes with the wallet password methods
+var passwordRoutes = map[string]string{
+	"/opendexrpc.XudInit/UnlockNode":  limiter.RuleKey(http.MethodPost, "/api/v1/opendexd/unlock"),
+	"/opendexrpc.XudInit/CreateNode":  limiter.RuleKey(http.MethodPost, "/api/v1/opendexd/create"),
+	"/opendexrpc.XudInit/RestoreNode": limiter.RuleKey(http.MethodPost, "/api/v1/opendexd/restore"),
+	"/opendexrpc.Xud/ChangePassword":  limiter.RuleKey(http.MethodPost, "/api/v1/opendexd/changepass"),
+}
@@ -0,0 +1,267 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	contentTypeWeb     = "application/grpc-web"
+	contentTypeWebText = "application/grpc-web-text"
+
+	// maxMessageSize is the default maximum message size of gRPC servers
+	maxMessageSize = 4 * 1024 * 1024
+
+	flagData    = 0x00
+	flagTrailer = 0x80
+)
+
+var (
+	// WebHeaders are the request headers of gRPC-Web clients allowed by CORS
+	WebHeaders = []string{"Authorization", "X-Grpc-Web", "X-User-Agent", "Grpc-Timeout", BackendHeader}
+	// WebExposedHeaders are the response headers of gRPC-Web which browsers must expose to the clients
+	WebExposedHeaders = []string{"Grpc-Status", "Grpc-Message"}
+
+	timeoutUnits = map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+)
+
+func (t *Gateway) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodPost, "/grpc-web/*method", "Forward a gRPC-Web call").
+		Tag("gateway").
+		Describe("Forwards a call of opendexrpc, lnrpc or boltzrpc (e.g. /grpc-web/opendexrpc.Xud/GetInfo) to the backend. The body is a gRPC-Web request (application/grpc-web+proto or application/grpc-web-text). The x-backend header selects lndltc or boltz.litecoin. The token scope is checked per method; client streaming methods are not supported.").
+		Produces(http.StatusOK, contentTypeWeb+"+proto", nil, "gRPC-Web response")
+}
+
+func (t *Gateway) ConfigureRouter(r *gin.Engine) {
+	r.POST("/grpc-web/*method", t.handleWeb)
+}
+
+// parseTimeout parses the grpc-timeout header, e.g. 10S or 500m
+func parseTimeout(value string) (time.Duration, bool) {
+	if len(value) < 2 {
+		return 0, false
+	}
+	unit, ok := timeoutUnits[value[len(value)-1]]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(value[:len(value)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// encodeMessage percent-encodes a grpc-message value
+func encodeMessage(msg string) string {
+	var b strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if c >= 0x20 && c <= 0x7e && c != '%' {
+			b.WriteByte(c)
+		} else {
+			_, _ = fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// readMessage reads the first data frame of a gRPC-Web request
+func readMessage(r io.Reader) (*frame, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read message: %s", err)
+	}
+	if header[0] != flagData {
+		return nil, status.Error(codes.Unimplemented, "compressed messages are not supported")
+	}
+	n := binary.BigEndian.Uint32(header[1:])
+	if n > maxMessageSize {
+		return nil, status.Errorf(codes.ResourceExhausted, "message larger than %d bytes", maxMessageSize)
+	}
+	f := &frame{payload: make([]byte, n)}
+	if _, err := io.ReadFull(r, f.payload); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to read message: %s", err)
+	}
+	return f, nil
+}
+
+type webWriter struct {
+	w    gin.ResponseWriter
+	text bool
+}
+
+func (t *webWriter) write(flag byte, payload []byte) error {
+	buf := make([]byte, 5+len(payload))
+	buf[0] = flag
+	binary.BigEndian.PutUint32(buf[1:5], uint32(len(payload)))
+	copy(buf[5:], payload)
+	if t.text {
+		// every frame is encoded on its own so that it can be flushed
+		buf = []byte(base64.StdEncoding.EncodeToString(buf))
+	}
+	if _, err := t.w.Write(buf); err != nil {
+		return err
+	}
+	t.w.Flush()
+	return nil
+}
+
+func (t *webWriter) writeTrailer(err error, trailer metadata.MD) error {
+	s := status.Convert(err)
+	var b bytes.Buffer
+	_, _ = fmt.Fprintf(&b, "grpc-status: %d\r\n", s.Code())
+	_, _ = fmt.Fprintf(&b, "grpc-message: %s\r\n", encodeMessage(s.Message()))
+	for k, values := range trailer {
+		for _, v := range values {
+			_, _ = fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+		}
+	}
+	return t.write(flagTrailer, b.Bytes())
+}
+
+// webError answers a call which failed before the response with the HTTP status of the code, so that rejected
+// calls are accounted by the rate limiter and the audit log like REST requests
+func webError(c *gin.Context, err error) {
+	s := status.Convert(err)
+	_ = c.Error(errors.New(s.Message()))
+	c.Header("Content-Type", contentTypeWeb+"+proto")
+	c.Header("Grpc-Status", strconv.Itoa(int(s.Code())))
+	c.Header("Grpc-Message", encodeMessage(s.Message()))
+	c.Status(httpStatus(s.Code()))
+}
+
+func (t *Gateway) handleWeb(c *gin.Context) {
+	contentType := c.ContentType()
+	text := strings.HasPrefix(contentType, contentTypeWebText)
+	if !text && !strings.HasPrefix(contentType, contentTypeWeb) {
+		utils.JsonError(c, fmt.Sprintf("unsupported content type: %s", contentType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var chains [][]*x509.Certificate
+	if c.Request.TLS != nil {
+		chains = c.Request.TLS.VerifiedChains
+	}
+	call, err := t.begin(c.Param("method"), auth.ExtractToken(c.Request), chains, c.ClientIP())
+	if err != nil {
+		webError(c, err)
+		return
+	}
+	if call.method.desc.IsStreamingClient() {
+		webError(c, status.Errorf(codes.Unimplemented, "%s is client streaming which gRPC-Web does not support", call.method.name))
+		return
+	}
+
+	var body io.Reader = c.Request.Body
+	if text {
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	msg, err := readMessage(body)
+	if err != nil {
+		webError(c, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	if timeout, ok := parseTimeout(c.GetHeader("Grpc-Timeout")); ok {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
+
+	client, err := t.open(ctx, call, c.GetHeader(BackendHeader))
+	if err != nil {
+		webError(c, err)
+		return
+	}
+	err = t.respond(c, client, msg, text)
+	t.end(call, err)
+}
+
+// respond forwards the request message and writes the response messages as they arrive. A call failing before the
+// first message is answered like webError.
+func (t *Gateway) respond(c *gin.Context, client grpc.ClientStream, msg *frame, text bool) error {
+	// a failed send ends the call, RecvMsg returns the status
+	_ = client.SendMsg(msg)
+	_ = client.CloseSend()
+
+	w := &webWriter{w: c.Writer, text: text}
+	started := false
+	start := func() {
+		header, _ := client.Header()
+		for k, values := range header {
+			if k == "content-type" {
+				continue
+			}
+			for _, v := range values {
+				c.Writer.Header().Add(k, v)
+			}
+		}
+		if text {
+			c.Header("Content-Type", contentTypeWebText+"+proto")
+		} else {
+			c.Header("Content-Type", contentTypeWeb+"+proto")
+		}
+		c.Status(http.StatusOK)
+		started = true
+	}
+
+	var err error
+	for {
+		f := &frame{}
+		if err = client.RecvMsg(f); err != nil {
+			break
+		}
+		if !started {
+			start()
+		}
+		if err := w.write(flagData, f.payload); err != nil {
+			// the client went away
+			return err
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	if !started {
+		if err != nil {
+			webError(c, err)
+			return err
+		}
+		start()
+	}
+	if err != nil {
+		_ = c.Error(errors.New(status.Convert(err).Message()))
+	}
+	if err := w.writeTrailer(err, client.Trailer()); err != nil {
+		t.logger.Debugf("Failed to write trailer: %s", err)
+	}
+	return err
+}
@@ -1,14 +1,23 @@
 package launcher
 
 import (
+	"encoding/json"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
 	"github.com/opendexnetwork/opendex-docker-api/utils"
 	"github.com/gin-gonic/contrib/static"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
-func ConfigureRouter(r *gin.Engine) {
-	r.Use(static.Serve("/", static.LocalFile("/ui", false)))
+func ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/info", "Get the information of the attached launcher").
+		Tag("launcher").Returns(json.RawMessage{})
+	spec.Route(http.MethodPut, "/api/v1/backup", "Change the backup location").
+		Tag("launcher").Body(BackupSettings{}).NoContent()
+}
+
+func ConfigureRouter(r *gin.Engine, uiDir string) {
+	r.Use(static.Serve("/", static.LocalFile(uiDir, false)))
 
 	api := r.Group("/api")
 	{
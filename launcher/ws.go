@@ -1,11 +1,14 @@
 package launcher
 
 import (
+"context"
 "encoding/json"
 "fmt"
 "github.com/gorilla/websocket"
 	"github.com/sirupsen/logrus"
 	"net/http"
+	"sync"
+	"time"
 )
 
 var (
@@ -14,12 +17,17 @@ var (
 		WriteBufferSize: 1024,
 	}
 
+	// launchersMutex guards launchers, which WsHandler and Listen change while the API reads them
 	launchers []*Launcher
+	launchersMutex = &sync.Mutex{}
 	requests = make(chan LauncherRequest)
 
 	logger *logrus.Entry
 
 	errNoLauncher = fmt.Errorf("no launcher")
+
+	// RequestTimeout is how long to wait for the response of the launcher
+	RequestTimeout = 5 * time.Minute
 )
 
 func init() {
@@ -34,6 +42,8 @@ type RequestHandler interface {
 type Launcher struct {
 	conn *websocket.Conn
 
+	// mutex guards counter and requests and serializes the writes to conn
+	mutex *sync.Mutex
 	counter uint16
 	requests map[uint16]chan Response
 }
@@ -66,19 +76,45 @@ func WsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logger.Debugf("New launcher attached. Launchers = %d", len(launchers))
-
 	launcher := Launcher{
 		conn: conn,
+		mutex: &sync.Mutex{},
 		counter: 0,
 		requests: make(map[uint16]chan Response),
 	}
 
+	launchersMutex.Lock()
 	launchers = append(launchers, &launcher)
+	logger.Debugf("New launcher attached. Launchers = %d", len(launchers))
+	launchersMutex.Unlock()
 
 	go launcher.Listen()
 }
 
+// firstLauncher returns the launcher which the requests are sent to
+func firstLauncher() (*Launcher, error) {
+	launchersMutex.Lock()
+	defer launchersMutex.Unlock()
+	if len(launchers) == 0 {
+		return nil, errNoLauncher
+	}
+	return launchers[0], nil
+}
+
+// CloseLaunchers tells the attached launchers that the proxy is going away and closes their connections
+func CloseLaunchers() {
+	launchersMutex.Lock()
+	attached := make([]*Launcher, len(launchers))
+	copy(attached, launchers)
+	launchersMutex.Unlock()
+
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "proxy shutting down")
+	for _, launcher := range attached {
+		_ = launcher.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+		_ = launcher.conn.Close()
+	}
+}
+
 func StartLauncherRegistry() {
 	for {
 		req := <-requests
@@ -86,34 +122,64 @@ func StartLauncherRegistry() {
 	}
 }
 
-func GetInfo() (interface{}, error) {
-	if len(launchers) == 0 {
-		return nil, errNoLauncher
-	}
-	return launchers[0].GetInfo()
-}
+// call sends a request and waits for the response until ctx is done. The response channel is registered before the
+// request is written so that a fast response can't be missed.
+func (t *Launcher) call(ctx context.Context, method string, params []string) (*Response, error) {
+	// buffered so that Listen never blocks on a request which timed out
+	respChan := make(chan Response, 1)
 
-func (t *Launcher) GetInfo() (*json.RawMessage, error){
+	t.mutex.Lock()
 	t.counter += 1
 	req := Request{
 		Id: t.counter,
-		Method: "getinfo",
-		Params: []string{},
+		Method: method,
+		Params: params,
 	}
+	t.requests[req.Id] = respChan
 	payload, err := json.Marshal(req)
+	if err == nil {
+		err = t.conn.WriteMessage(websocket.TextMessage, payload)
+	}
+	t.mutex.Unlock()
+
+	defer func() {
+		t.mutex.Lock()
+		delete(t.requests, req.Id)
+		t.mutex.Unlock()
+	}()
+
 	if err != nil {
 		return nil, err
 	}
-	t.conn.WriteMessage(websocket.TextMessage, payload)
 
-	respChan := make(chan Response)
+	select {
+	case resp := <-respChan:
+		return &resp, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("launcher %s: %w", method, ctx.Err())
+	}
+}
 
-	t.requests[req.Id] = respChan
+// callWithTimeout is call with RequestTimeout
+func (t *Launcher) callWithTimeout(method string, params []string) (*Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
+	defer cancel()
+	return t.call(ctx, method, params)
+}
 
-	resp := <-respChan
+func GetInfo() (interface{}, error) {
+	launcher, err := firstLauncher()
+	if err != nil {
+		return nil, err
+	}
+	return launcher.GetInfo()
+}
 
-	close(respChan)
-	delete(t.requests, req.Id)
+func (t *Launcher) GetInfo() (*json.RawMessage, error){
+	resp, err := t.callWithTimeout("getinfo", []string{})
+	if err != nil {
+		return nil, err
+	}
 
 	var info json.RawMessage
 
@@ -130,41 +196,69 @@ type BackupSettings struct {
 }
 
 func UpdateBackup(settings BackupSettings) (bool, error) {
-	if len(launchers) == 0 {
-		return false, errNoLauncher
+	launcher, err := firstLauncher()
+	if err != nil {
+		return false, err
 	}
-	return launchers[0].UpdateBackup(settings)
+	return launcher.UpdateBackup(settings)
 }
 
 func (t *Launcher) UpdateBackup(settings BackupSettings) (bool, error) {
-	t.counter += 1
-	req := Request{
-		Id: t.counter,
-		Method: "backupto",
-		Params: []string{settings.Location},
-	}
-	payload, err := json.Marshal(req)
+	resp, err := t.callWithTimeout("backupto", []string{settings.Location})
 	if err != nil {
 		return false, err
 	}
-	t.conn.WriteMessage(websocket.TextMessage, payload)
 
-	respChan := make(chan Response)
+	if resp.Error == nil {
+		return true, nil
+	}
 
-	t.requests[req.Id] = respChan
+	return false, fmt.Errorf("%s", resp.Error)
+}
 
-	resp := <-respChan
+// SetEthProvider asks the launcher to recreate connext with another Ethereum provider. It gives up waiting for the
+// launcher when ctx is done.
+func SetEthProvider(ctx context.Context, provider string) error {
+	launcher, err := firstLauncher()
+	if err != nil {
+		return err
+	}
+	return launcher.SetEthProvider(ctx, provider)
+}
 
-	close(respChan)
-	delete(t.requests, req.Id)
+func (t *Launcher) SetEthProvider(ctx context.Context, provider string) error {
+	resp, err := t.call(ctx, "setethprovider", []string{provider})
+	if err != nil {
+		return err
+	}
 
-	if resp.Error == nil {
-		return true, nil
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error)
 	}
+	return nil
+}
 
-	return false, fmt.Errorf("%s", resp.Error)
+// Recreate asks the launcher to recreate the containers of services after their settings in config.json changed. It
+// gives up waiting for the launcher when ctx is done.
+func Recreate(ctx context.Context, services []string) error {
+	launcher, err := firstLauncher()
+	if err != nil {
+		return err
+	}
+	return launcher.Recreate(ctx, services)
 }
 
+func (t *Launcher) Recreate(ctx context.Context, services []string) error {
+	resp, err := t.call(ctx, "recreate", services)
+	if err != nil {
+		return err
+	}
+
+	if resp.Error != nil {
+		return fmt.Errorf("%s", resp.Error)
+	}
+	return nil
+}
 
 func (t *Launcher) Listen() {
 	for {
@@ -172,11 +266,14 @@ func (t *Launcher) Listen() {
 		if err != nil {
 			logger.Debugf("Failed to listen for messages from launcher: %s", err)
 			// remove launcher from launchers
+			launchersMutex.Lock()
 			for i, launcher := range launchers {
 				if launcher == t {
 					launchers = append(launchers[:i], launchers[i+1:]...)
+					break
 				}
 			}
+			launchersMutex.Unlock()
 			break
 		}
 		if msgType == websocket.TextMessage {
@@ -197,7 +294,14 @@ func (t *Launcher) Listen() {
 			} else {
 
 				logger.Debugf("[Attach] The message is a response")
-				t.requests[resp.Id] <- resp
+				t.mutex.Lock()
+				respChan, ok := t.requests[resp.Id]
+				t.mutex.Unlock()
+				if ok {
+					respChan <- resp
+				} else {
+					logger.Debugf("[Attach] No pending request %d (timed out)", resp.Id)
+				}
 
 			}
 		} else {
@@ -0,0 +1,31 @@
+package limiter
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"net/http"
+)
+
+func (t *Limiter) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/lockouts", "List the clients with failures or lockouts").
+		Tag("limiter").Returns([]Lockout{})
+	spec.Route(http.MethodDelete, "/api/v1/lockouts/:subject", "Clear the failures and lockouts of a client").
+		Tag("limiter").PathParam("subject", "e.g. ip:1.2.3.4").NoContent()
+}
+
+func (t *Limiter) ConfigureRouter(r *gin.Engine) {
+	api := r.Group("/api")
+	{
+		api.GET("/v1/lockouts", func(c *gin.Context) {
+			c.JSON(http.StatusOK, t.Lockouts())
+		})
+		api.DELETE("/v1/lockouts/:subject", func(c *gin.Context) {
+			if !t.Clear(c.Param("subject")) {
+				utils.JsonError(c, "subject not found", http.StatusNotFound)
+				return
+			}
+			c.Status(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package limiter
+
+import (
+	"github.com/sirupsen/logrus"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type entry struct {
+	failures    int
+	lockouts    int
+	lockedUntil time.Time
+	lastFailure time.Time
+}
+
+// Lockout describes a client which is (or has recently been) locked out
+type Lockout struct {
+	Rule        string    `json:"rule"`
+	Subject     string    `json:"subject"`
+	Failures    int       `json:"failures"`
+	Lockouts    int       `json:"lockouts"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+// Limiter keeps token buckets and failure counters for every (rule, subject) pair. A subject is a client IP
+// ("ip:1.2.3.4") or an API token ("token:<hash prefix>").
+type Limiter struct {
+	rules   map[string]Rule
+	buckets map[string]*bucket
+	entries map[string]*entry
+	mutex   *sync.Mutex
+	now     func() time.Time
+
+	logger *logrus.Entry
+}
+
+func NewLimiter(rules map[string]Rule) *Limiter {
+	l := &Limiter{
+		rules:   rules,
+		buckets: map[string]*bucket{},
+		entries: map[string]*entry{},
+		mutex:   &sync.Mutex{},
+		now:     time.Now,
+		logger:  logrus.NewEntry(logrus.StandardLogger()).WithField("name", "limiter"),
+	}
+	go l.cleanup()
+	return l
+}
+
+func entryKey(rule string, subject string) string {
+	return rule + "|" + subject
+}
+
+// SetRule adds or replaces the rule of a route ("METHOD /path" or DefaultRuleName)
+func (t *Limiter) SetRule(route string, rule Rule) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.rules[route] = rule
+}
+
+// ApplyRule parses a rule (see ParseRule) on top of the current rule of the route and sets it
+func (t *Limiter) ApplyRule(value string) error {
+	route, rule, err := ParseRule(value, func(route string) Rule {
+		_, rule := t.RuleOf(route)
+		return rule
+	})
+	if err != nil {
+		return err
+	}
+	t.SetRule(route, rule)
+	return nil
+}
+
+// RuleOf returns the name and the rule applied to a route
+func (t *Limiter) RuleOf(route string) (string, Rule) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if rule, ok := t.rules[route]; ok {
+		return route, rule
+	}
+	return DefaultRuleName, t.rules[DefaultRuleName]
+}
+
+// LockedUntil returns the end of the longest lockout of the subjects for the rule (or the default rule)
+func (t *Limiter) LockedUntil(rule string, subjects ...string) time.Time {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var result time.Time
+	now := t.now()
+	for _, name := range []string{rule, DefaultRuleName} {
+		for _, subject := range subjects {
+			e, ok := t.entries[entryKey(name, subject)]
+			if ok && e.lockedUntil.After(now) && e.lockedUntil.After(result) {
+				result = e.lockedUntil
+			}
+		}
+	}
+	return result
+}
+
+// Allow takes a token from the bucket of every subject. It returns false with the time to wait when one of them is
+// empty.
+func (t *Limiter) Allow(name string, rule Rule, subjects ...string) (bool, time.Duration) {
+	if rule.Rate <= 0 {
+		return true, 0
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := t.now()
+	burst := math.Max(float64(rule.Burst), 1)
+	var wait time.Duration
+	var buckets []*bucket
+	for _, subject := range subjects {
+		key := entryKey(name, subject)
+		b, ok := t.buckets[key]
+		if !ok {
+			b = &bucket{tokens: burst, last: now}
+			t.buckets[key] = b
+		}
+		b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rule.Rate)
+		b.last = now
+		if b.tokens < 1 {
+			w := time.Duration((1 - b.tokens) / rule.Rate * float64(time.Second))
+			if w > wait {
+				wait = w
+			}
+		}
+		buckets = append(buckets, b)
+	}
+	if wait > 0 {
+		return false, wait
+	}
+	for _, b := range buckets {
+		b.tokens -= 1
+	}
+	return true, 0
+}
+
+// Fail records a failure of the subjects and locks them out once the rule's MaxFailures is reached
+func (t *Limiter) Fail(name string, rule Rule, subjects ...string) {
+	if rule.MaxFailures <= 0 {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := t.now()
+	for _, subject := range subjects {
+		key := entryKey(name, subject)
+		e, ok := t.entries[key]
+		if !ok {
+			e = &entry{}
+			t.entries[key] = e
+		}
+		e.failures += 1
+		e.lastFailure = now
+		if e.failures >= rule.MaxFailures {
+			d := rule.Lockout * time.Duration(1<<uint(e.lockouts))
+			if rule.MaxLockout > 0 && (d > rule.MaxLockout || d <= 0) {
+				d = rule.MaxLockout
+			}
+			e.lockedUntil = now.Add(d)
+			e.lockouts += 1
+			e.failures = 0
+			t.logger.Warnf("Locked out %s on %s for %s after %d failures", subject, name, d, rule.MaxFailures)
+		}
+	}
+}
+
+// Succeed resets the consecutive failures of the subjects. The lockout count is kept so that the next lockout is
+// still longer.
+func (t *Limiter) Succeed(name string, subjects ...string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, subject := range subjects {
+		if e, ok := t.entries[entryKey(name, subject)]; ok {
+			e.failures = 0
+		}
+	}
+}
+
+// Lockouts lists active lockouts and subjects with failures
+func (t *Limiter) Lockouts() []Lockout {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	result := []Lockout{}
+	now := t.now()
+	for key, e := range t.entries {
+		if e.failures == 0 && !e.lockedUntil.After(now) {
+			continue
+		}
+		rule, subject := splitEntryKey(key)
+		result = append(result, Lockout{
+			Rule:        rule,
+			Subject:     subject,
+			Failures:    e.failures,
+			Lockouts:    e.lockouts,
+			LockedUntil: e.lockedUntil,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LockedUntil.After(result[j].LockedUntil)
+	})
+	return result
+}
+
+// Clear removes all lockouts and failures of a subject
+func (t *Limiter) Clear(subject string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	found := false
+	for key := range t.entries {
+		if _, s := splitEntryKey(key); s == subject {
+			delete(t.entries, key)
+			found = true
+		}
+	}
+	return found
+}
+
+func splitEntryKey(key string) (string, string) {
+	for i := len(key) - 1; i >= 0; i-- {
+		if key[i] == '|' {
+			return key[:i], key[i+1:]
+		}
+	}
+	return key, ""
+}
+
+// cleanup forgets idle buckets and entries which are no longer relevant
+func (t *Limiter) cleanup() {
+	ticker := time.NewTicker(10 * time.Minute)
+	for range ticker.C {
+		t.mutex.Lock()
+		now := t.now()
+		for key, b := range t.buckets {
+			if now.Sub(b.last) > time.Hour {
+				delete(t.buckets, key)
+			}
+		}
+		for key, e := range t.entries {
+			if now.Sub(e.lastFailure) > 24*time.Hour && !e.lockedUntil.After(now) {
+				delete(t.entries, key)
+			}
+		}
+		t.mutex.Unlock()
+	}
+}
@@ -0,0 +1,98 @@
+package limiter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func subjectsOf(c *gin.Context) []string {
+	return Subjects(c.ClientIP(), auth.ExtractToken(c.Request))
+}
+
+// Subjects returns the subjects accounted for a request from the client IP with the token secret (which may be empty)
+func Subjects(clientIp string, secret string) []string {
+	subjects := []string{"ip:" + clientIp}
+	if secret != "" {
+		// never keep the secret itself in memory or expose it through the lockouts endpoint
+		sum := sha256.Sum256([]byte(secret))
+		subjects = append(subjects, "token:"+hex.EncodeToString(sum[:8]))
+	}
+	return subjects
+}
+
+// IsPasswordError checks if a gRPC error returned by opendexd (or lnd) means the wallet password was wrong
+func IsPasswordError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch s.Code() {
+	case codes.Unauthenticated:
+		return true
+	case codes.InvalidArgument, codes.Unknown, codes.PermissionDenied:
+		msg := strings.ToLower(s.Message())
+		return strings.Contains(msg, "password") || strings.Contains(msg, "passphrase")
+	}
+	return false
+}
+
+func isPasswordFailure(c *gin.Context) bool {
+	for _, e := range c.Errors {
+		if IsPasswordError(e.Err) {
+			return true
+		}
+	}
+	return false
+}
+
+func tooManyRequests(c *gin.Context, wait time.Duration, message string) {
+	c.Header("Retry-After", fmt.Sprintf("%d", int(math.Ceil(wait.Seconds()))))
+	utils.JsonError(c, message, http.StatusTooManyRequests)
+	c.Abort()
+}
+
+// Middleware rejects locked out and too frequent clients. It goes before the auth middleware so that token guessing
+// is accounted too.
+func (t *Limiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if path == "" {
+			path = c.Request.URL.Path
+		}
+		name, rule := t.RuleOf(RuleKey(c.Request.Method, path))
+		subjects := subjectsOf(c)
+
+		if until := t.LockedUntil(name, subjects...); !until.IsZero() {
+			wait := until.Sub(t.now())
+			tooManyRequests(c, wait, fmt.Sprintf("too many failed attempts, locked out until %s", until.UTC().Format(time.RFC3339)))
+			return
+		}
+
+		if ok, wait := t.Allow(name, rule, subjects...); !ok {
+			tooManyRequests(c, wait, "too many requests")
+			return
+		}
+
+		c.Next()
+
+		code := c.Writer.Status()
+		if code == http.StatusUnauthorized {
+			_, defaultRule := t.RuleOf(DefaultRuleName)
+			t.Fail(DefaultRuleName, defaultRule, subjects...)
+		} else if isPasswordFailure(c) {
+			t.Fail(name, rule, subjects...)
+		} else if code < 400 {
+			t.Succeed(name, subjects...)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package limiter
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultRuleName is the rule applied to routes without their own rule. Authentication failures (401) are always
+// accounted to it.
+const DefaultRuleName = "*"
+
+type Rule struct {
+	// Rate is the number of requests per second allowed for one client. Zero disables rate limiting.
+	Rate float64 `json:"rate"`
+	// Burst is the bucket size of the rate limiter
+	Burst int `json:"burst"`
+	// MaxFailures is the number of consecutive failures before a client gets locked out. Zero disables lockouts.
+	MaxFailures int `json:"maxFailures"`
+	// Lockout is the first lockout duration. It doubles on every following lockout up to MaxLockout.
+	Lockout    time.Duration `json:"lockout"`
+	MaxLockout time.Duration `json:"maxLockout"`
+}
+
+func RuleKey(method string, path string) string {
+	return fmt.Sprintf("%s %s", method, path)
+}
+
+// DefaultRules throttles the wallet password routes and locks out clients guessing passwords or tokens
+func DefaultRules() map[string]Rule {
+	password := Rule{
+		Rate:        0.2,
+		Burst:       5,
+		MaxFailures: 3,
+		Lockout:     30 * time.Second,
+		MaxLockout:  time.Hour,
+	}
+	return map[string]Rule{
+		DefaultRuleName: {
+			Rate:        0,
+			Burst:       0,
+			MaxFailures: 10,
+			Lockout:     time.Minute,
+			MaxLockout:  time.Hour,
+		},
+		RuleKey(http.MethodPost, "/api/v1/opendexd/unlock"):     password,
+		RuleKey(http.MethodPost, "/api/v1/opendexd/changepass"): password,
+		RuleKey(http.MethodPost, "/api/v1/opendexd/create"):     password,
+		RuleKey(http.MethodPost, "/api/v1/opendexd/restore"):    password,
+	}
+}
+
+// ParseRule parses a rule in the form "METHOD /path:rate=0.2,burst=5,failures=3,lockout=30s,maxLockout=1h". Omitted
+// options take their values from the base function. Use "*" as the route to change the default rule.
+func ParseRule(value string, base func(route string) Rule) (string, Rule, error) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return "", Rule{}, fmt.Errorf("invalid rule %q: missing options", value)
+	}
+	key := strings.TrimSpace(value[:i])
+	rule := base(key)
+	for _, option := range strings.Split(value[i+1:], ",") {
+		kv := strings.SplitN(strings.TrimSpace(option), "=", 2)
+		if len(kv) != 2 {
+			return "", Rule{}, fmt.Errorf("invalid rule %q: bad option %q", value, option)
+		}
+		var err error
+		switch kv[0] {
+		case "rate":
+			rule.Rate, err = strconv.ParseFloat(kv[1], 64)
+		case "burst":
+			rule.Burst, err = strconv.Atoi(kv[1])
+		case "failures":
+			rule.MaxFailures, err = strconv.Atoi(kv[1])
+		case "lockout":
+			rule.Lockout, err = time.ParseDuration(kv[1])
+		case "maxLockout":
+			rule.MaxLockout, err = time.ParseDuration(kv[1])
+		default:
+			err = fmt.Errorf("unknown option")
+		}
+		if err != nil {
+			return "", Rule{}, fmt.Errorf("invalid rule %q: %s: %s", value, kv[0], err)
+		}
+	}
+	return key, rule, nil
+}
@@ -0,0 +1,33 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func (t *Spec) ConfigureRouter(r *gin.Engine) {
+	api := r.Group("/api")
+	{
+		api.GET("/openapi.json", func(c *gin.Context) {
+			t.mutex.Lock()
+			data, err := json.Marshal(t.doc)
+			t.mutex.Unlock()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, ErrorResult{Message: err.Error()})
+				return
+			}
+			c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+		})
+		api.GET("/docs", func(c *gin.Context) {
+			c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+		})
+	}
+
+	t.Route(http.MethodGet, "/api/openapi.json", "Get this OpenAPI document").
+		Tag("docs").
+		Produces(http.StatusOK, ContentTypeJson, nil, "The OpenAPI 3 document")
+	t.Route(http.MethodGet, "/api/docs", "Browse the API documentation").
+		Tag("docs").
+		Produces(http.StatusOK, "text/html", nil, "The documentation page")
+}
@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+	timeType         = reflect.TypeOf(time.Time{})
+	rawMessageType   = reflect.TypeOf(json.RawMessage{})
+)
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// schemaOf returns the schema of a Go value as encoded by encoding/json, or by jsonpb for protobuf messages. Named
+// structs and messages are registered as components and referenced.
+func (t *Spec) schemaOf(typ reflect.Type) *Schema {
+	if typ.Implements(protoMessageType) {
+		m := reflect.New(typ.Elem()).Interface().(proto.Message)
+		return t.messageSchema(proto.MessageV2(m).ProtoReflect().Descriptor())
+	}
+
+	switch typ {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch typ.Kind() {
+	case reflect.Ptr:
+		return t.schemaOf(typ.Elem())
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: t.schemaOf(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: t.schemaOf(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return t.structSchema(typ)
+		}
+		name := path.Base(typ.PkgPath()) + "." + typ.Name()
+		if _, ok := t.doc.Components.Schemas[name]; !ok {
+			// register before expanding the fields in case the type is recursive
+			t.doc.Components.Schemas[name] = &Schema{}
+			*t.doc.Components.Schemas[name] = *t.structSchema(typ)
+		}
+		return ref(name)
+	}
+	return &Schema{}
+}
+
+func (t *Spec) structSchema(typ reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	t.addFields(s, typ)
+	return s
+}
+
+func (t *Spec) addFields(s *Schema, typ reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				t.addFields(s, embedded)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// unexported
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs := t.schemaOf(f.Type)
+		for _, option := range parts[1:] {
+			if option == "string" {
+				fs = &Schema{Type: "string"}
+			}
+		}
+		s.Properties[name] = fs
+	}
+}
+
+// messageSchema follows the jsonpb encoding: lowerCamelCase names, 64-bit integers as strings and enums as names
+func (t *Spec) messageSchema(md protoreflect.MessageDescriptor) *Schema {
+	name := string(md.FullName())
+	if _, ok := t.doc.Components.Schemas[name]; ok {
+		return ref(name)
+	}
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	t.doc.Components.Schemas[name] = s
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsMap() {
+			s.Properties[fd.JSONName()] = &Schema{Type: "object", AdditionalProperties: t.fieldSchema(fd.MapValue())}
+		} else if fd.IsList() {
+			s.Properties[fd.JSONName()] = &Schema{Type: "array", Items: t.fieldSchema(fd)}
+		} else {
+			s.Properties[fd.JSONName()] = t.fieldSchema(fd)
+		}
+	}
+	return ref(name)
+}
+
+func (t *Spec) fieldSchema(fd protoreflect.FieldDescriptor) *Schema {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int64"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind:
+		return &Schema{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return &Schema{Type: "number", Format: "double"}
+	case protoreflect.StringKind:
+		return &Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		var enum []interface{}
+		values := fd.Enum().Values()
+		for i := 0; i < values.Len(); i++ {
+			enum = append(enum, string(values.Get(i).Name()))
+		}
+		return &Schema{Type: "string", Enum: enum}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return t.messageSchema(fd.Message())
+	}
+	return &Schema{}
+}
@@ -0,0 +1,298 @@
+package openapi
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	ContentTypeJson   = "application/json"
+	ContentTypeNdjson = "application/x-ndjson"
+	ContentTypeText   = "text/plain"
+)
+
+// Spec builds the OpenAPI document of the REST routes. Every ConfigureRouter has a ConfigureSpec counterpart which
+// describes the routes it registers.
+type Spec struct {
+	doc   *Document
+	mutex *sync.Mutex
+}
+
+type ErrorResult struct {
+	Message string `json:"message"`
+}
+
+func NewSpec(title string, version string) *Spec {
+	s := &Spec{
+		doc: &Document{
+			OpenApi: "3.0.3",
+			Info: Info{
+				Title:   title,
+				Version: version,
+			},
+			Paths: map[string]*PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{},
+				SecuritySchemes: map[string]*SecurityScheme{
+					"bearerAuth": {
+						Type:        "http",
+						Scheme:      "bearer",
+						Description: "An API token (--auth)",
+					},
+					"queryToken": {
+						Type:        "apiKey",
+						Name:        "token",
+						In:          "query",
+						Description: "An API token for clients which cannot set headers (e.g. WebSocket)",
+					},
+				},
+			},
+		},
+		mutex: &sync.Mutex{},
+	}
+	s.schemaOf(reflect.TypeOf(ErrorResult{}))
+	return s
+}
+
+// toOpenApiPath converts a gin route pattern (/v1/logs/:service) to an OpenAPI path (/v1/logs/{service})
+func toOpenApiPath(route string) (string, []string) {
+	var params []string
+	parts := strings.Split(route, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, ":") || strings.HasPrefix(part, "*") {
+			params = append(params, part[1:])
+			parts[i] = "{" + part[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/"), params
+}
+
+func toRoutePath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			parts[i] = ":" + part[1:len(part)-1]
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func operationId(method string, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '{' || r == '}' || r == '-' || r == '.' || r == ':'
+	}) {
+		if part == "api" || part == "v1" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// Route describes a route. The path is the full gin route pattern (e.g. /api/v1/logs/:service) and its parameters
+// are added as required string path parameters.
+func (t *Spec) Route(method string, route string, summary string) *Operation {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	path, params := toOpenApiPath(route)
+	op := &Operation{
+		Summary:     summary,
+		OperationId: operationId(method, path),
+		Responses: map[string]*Response{
+			"default": {
+				Description: "Error",
+				Content:     map[string]*MediaType{ContentTypeJson: {Schema: ref("openapi.ErrorResult")}},
+			},
+		},
+		spec: t,
+	}
+	for _, name := range params {
+		op.Parameters = append(op.Parameters, &Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	item, ok := t.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		t.doc.Paths[path] = item
+	}
+	(*item)[strings.ToLower(method)] = op
+	return op
+}
+
+// Walk calls f with the gin route pattern of every operation
+func (t *Spec) Walk(f func(method string, route string, op *Operation)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for path, item := range t.doc.Paths {
+		for method, op := range *item {
+			f(strings.ToUpper(method), toRoutePath(path), op)
+		}
+	}
+}
+
+// Missing returns the /api routes registered in gin without a spec entry
+func (t *Spec) Missing(routes gin.RoutesInfo) []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	var result []string
+	for _, r := range routes {
+		if !strings.HasPrefix(r.Path, "/api/") {
+			continue
+		}
+		path, _ := toOpenApiPath(r.Path)
+		if item, ok := t.doc.Paths[path]; ok {
+			if _, ok := (*item)[strings.ToLower(r.Method)]; ok {
+				continue
+			}
+		}
+		result = append(result, fmt.Sprintf("%s %s", r.Method, r.Path))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (t *Spec) Document() *Document {
+	return t.doc
+}
+
+func (t *Operation) Tag(tags ...string) *Operation {
+	t.Tags = append(t.Tags, tags...)
+	return t
+}
+
+func (t *Operation) Describe(description string) *Operation {
+	t.Description = description
+	return t
+}
+
+// PathParam describes a path parameter added by Route
+func (t *Operation) PathParam(name string, description string) *Operation {
+	for _, p := range t.Parameters {
+		if p.In == "path" && p.Name == name {
+			p.Description = description
+		}
+	}
+	return t
+}
+
+// Query adds a query parameter. The value is used for its type.
+func (t *Operation) Query(name string, value interface{}, description string) *Operation {
+	t.spec.mutex.Lock()
+	defer t.spec.mutex.Unlock()
+	t.Parameters = append(t.Parameters, &Parameter{
+		Name:        name,
+		In:          "query",
+		Description: description,
+		Schema:      t.spec.schemaOf(reflect.TypeOf(value)),
+	})
+	return t
+}
+
+// QueryParams adds the fields of a struct bound with c.BindQuery (the form tags) as query parameters
+func (t *Operation) QueryParams(params interface{}) *Operation {
+	typ := reflect.TypeOf(params)
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name := strings.Split(f.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		t.Query(name, reflect.Zero(f.Type).Interface(), "")
+	}
+	return t
+}
+
+// Body sets the JSON request body
+func (t *Operation) Body(params interface{}) *Operation {
+	t.spec.mutex.Lock()
+	defer t.spec.mutex.Unlock()
+	t.RequestBody = &RequestBody{
+		Required: true,
+		Content:  map[string]*MediaType{ContentTypeJson: {Schema: t.spec.schemaOf(reflect.TypeOf(params))}},
+	}
+	return t
+}
+
+// Form sets a form request body from the form tags of a struct
+func (t *Operation) Form(params interface{}) *Operation {
+	t.spec.mutex.Lock()
+	defer t.spec.mutex.Unlock()
+	typ := reflect.TypeOf(params)
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		name := strings.Split(f.Tag.Get("form"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		s.Properties[name] = t.spec.schemaOf(f.Type)
+	}
+	t.RequestBody = &RequestBody{
+		Required: true,
+		Content:  map[string]*MediaType{"application/x-www-form-urlencoded": {Schema: s}},
+	}
+	return t
+}
+
+// Returns sets the JSON response with status 200. Protobuf messages are described as encoded by jsonpb.
+func (t *Operation) Returns(result interface{}) *Operation {
+	return t.ReturnsStatus(http.StatusOK, result)
+}
+
+func (t *Operation) ReturnsStatus(code int, result interface{}) *Operation {
+	t.spec.mutex.Lock()
+	defer t.spec.mutex.Unlock()
+	t.Responses[fmt.Sprint(code)] = &Response{
+		Description: http.StatusText(code),
+		Content:     map[string]*MediaType{ContentTypeJson: {Schema: t.spec.schemaOf(reflect.TypeOf(result))}},
+	}
+	return t
+}
+
+// Produces sets a non-JSON response. A nil item describes an opaque body, otherwise the body is a stream of items
+// (e.g. newline delimited JSON).
+func (t *Operation) Produces(code int, contentType string, item interface{}, description string) *Operation {
+	t.spec.mutex.Lock()
+	defer t.spec.mutex.Unlock()
+	var schema *Schema
+	if item == nil {
+		schema = &Schema{Type: "string", Format: "binary"}
+	} else {
+		schema = t.spec.schemaOf(reflect.TypeOf(item))
+	}
+	t.Responses[fmt.Sprint(code)] = &Response{
+		Description: description,
+		Content:     map[string]*MediaType{contentType: {Schema: schema}},
+	}
+	return t
+}
+
+// NoContent sets the 204 response
+func (t *Operation) NoContent() *Operation {
+	t.Responses[fmt.Sprint(http.StatusNoContent)] = &Response{Description: http.StatusText(http.StatusNoContent)}
+	return t
+}
+
+// RequireScope records the token scope required by the operation. Public operations have no security requirement.
+func (t *Operation) RequireScope(scope string) *Operation {
+	t.Scope = scope
+	if scope == "" {
+		t.Security = nil
+	} else {
+		t.Security = []map[string][]string{{"bearerAuth": {}}, {"queryToken": {}}}
+	}
+	return t
+}
@@ -0,0 +1,146 @@
+package openapi
+
+// docsPage renders /api/openapi.json without any external assets so that it works offline and behind Tor
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>opendex-docker-api</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; margin: 0; display: flex; height: 100vh; color: #222; }
+nav { width: 320px; overflow-y: auto; border-right: 1px solid #ddd; padding: 12px; box-sizing: border-box; background: #fafafa; }
+main { flex: 1; overflow-y: auto; padding: 16px 24px; }
+nav h3 { margin: 16px 0 4px; font-size: 13px; text-transform: uppercase; color: #888; }
+nav a { display: block; padding: 3px 0; color: #222; text-decoration: none; font-size: 13px; word-break: break-all; }
+nav a:hover { text-decoration: underline; }
+.method { display: inline-block; width: 52px; font-weight: bold; font-size: 11px; }
+.get { color: #2a7ae2; } .post { color: #2e9c48; } .put { color: #c98a00; } .delete { color: #d33; }
+pre { background: #f4f4f4; padding: 8px; overflow-x: auto; font-size: 12px; }
+table { border-collapse: collapse; font-size: 13px; } td, th { border: 1px solid #ddd; padding: 4px 8px; text-align: left; }
+input, textarea { font-family: monospace; font-size: 12px; } textarea { width: 100%; height: 120px; }
+.scope { font-size: 12px; color: #888; } #token { width: 100%; margin-bottom: 8px; }
+</style>
+</head>
+<body>
+<nav>
+<input id="token" placeholder="API token" autocomplete="off">
+<div id="nav"></div>
+</nav>
+<main id="main"><p>Loading...</p></main>
+<script>
+var doc;
+var token = document.getElementById("token");
+token.value = localStorage.getItem("proxy-token") || "";
+token.onchange = function () { localStorage.setItem("proxy-token", token.value); };
+
+function esc(s) { return String(s).replace(/[&<>"]/g, function (c) { return {"&": "&amp;", "<": "&lt;", ">": "&gt;", '"': "&quot;"}[c]; }); }
+
+function resolve(s) {
+  while (s && s.$ref) { s = doc.components.schemas[s.$ref.split("/").pop()]; }
+  return s || {};
+}
+
+// example builds a sample value of a schema
+function example(s, depth) {
+  s = resolve(s);
+  if (depth > 4) { return null; }
+  if (s.enum) { return s.enum[0]; }
+  switch (s.type) {
+    case "object":
+      if (s.additionalProperties) { return {"key": example(s.additionalProperties, depth + 1)}; }
+      var o = {};
+      Object.keys(s.properties || {}).forEach(function (k) { o[k] = example(s.properties[k], depth + 1); });
+      return o;
+    case "array": return [example(s.items, depth + 1)];
+    case "integer": return 0;
+    case "number": return 0.0;
+    case "boolean": return false;
+    case "string": return s.format === "int64" ? "0" : "";
+  }
+  return null;
+}
+
+function render(path, method) {
+  var op = doc.paths[path][method];
+  var h = "<h2><span class='method " + method + "'>" + method.toUpperCase() + "</span>" + esc(path) + "</h2>";
+  h += "<p>" + esc(op.summary || "") + "</p>";
+  if (op.description) { h += "<p>" + esc(op.description) + "</p>"; }
+  h += "<p class='scope'>Scope: " + esc(op["x-scope"] || "public") + "</p>";
+  if (op.parameters) {
+    h += "<h3>Parameters</h3><table><tr><th>Name</th><th>In</th><th>Type</th><th>Value</th><th>Description</th></tr>";
+    op.parameters.forEach(function (p, i) {
+      h += "<tr><td>" + esc(p.name) + (p.required ? " *" : "") + "</td><td>" + p.in + "</td><td>" + esc(resolve(p.schema).type || "") +
+        "</td><td><input id='p" + i + "'></td><td>" + esc(p.description || "") + "</td></tr>";
+    });
+    h += "</table>";
+  }
+  var body = op.requestBody && op.requestBody.content["application/json"];
+  if (body) {
+    h += "<h3>Request body</h3><textarea id='body'>" + esc(JSON.stringify(example(body.schema, 0), null, 2)) + "</textarea>";
+  }
+  h += "<h3>Responses</h3>";
+  Object.keys(op.responses).forEach(function (code) {
+    var r = op.responses[code];
+    h += "<p><b>" + code + "</b> " + esc(r.description) + "</p>";
+    Object.keys(r.content || {}).forEach(function (type) {
+      var s = r.content[type].schema;
+      h += "<p class='scope'>" + esc(type) + "</p>";
+      if (s && s.format !== "binary") { h += "<pre>" + esc(JSON.stringify(example(s, 0), null, 2)) + "</pre>"; }
+    });
+  });
+  h += "<h3>Try it</h3><button id='send'>Send</button><pre id='result'></pre>";
+  var main = document.getElementById("main");
+  main.innerHTML = h;
+  main.scrollTop = 0;
+  document.getElementById("send").onclick = function () { send(path, method, op); };
+}
+
+function send(path, method, op) {
+  var url = path, query = [];
+  (op.parameters || []).forEach(function (p, i) {
+    var v = document.getElementById("p" + i).value;
+    if (p.in === "path") { url = url.replace("{" + p.name + "}", encodeURIComponent(v)); }
+    else if (v !== "") { query.push(encodeURIComponent(p.name) + "=" + encodeURIComponent(v)); }
+  });
+  if (query.length) { url += "?" + query.join("&"); }
+  var init = {method: method.toUpperCase(), headers: {}};
+  if (token.value) { init.headers["Authorization"] = "Bearer " + token.value; }
+  var body = document.getElementById("body");
+  if (body) { init.body = body.value; init.headers["Content-Type"] = "application/json"; }
+  var result = document.getElementById("result");
+  result.textContent = "...";
+  fetch(url, init).then(function (resp) {
+    return resp.text().then(function (text) { result.textContent = resp.status + " " + resp.statusText + "\n\n" + text; });
+  }).catch(function (e) { result.textContent = String(e); });
+}
+
+fetch("openapi.json").then(function (r) { return r.json(); }).then(function (d) {
+  doc = d;
+  var groups = {};
+  Object.keys(doc.paths).sort().forEach(function (path) {
+    Object.keys(doc.paths[path]).forEach(function (method) {
+      var tag = (doc.paths[path][method].tags || ["other"])[0];
+      (groups[tag] = groups[tag] || []).push([path, method]);
+    });
+  });
+  var h = "";
+  Object.keys(groups).sort().forEach(function (tag) {
+    h += "<h3>" + esc(tag) + "</h3>";
+    groups[tag].forEach(function (e) {
+      h += "<a href='#' data-path='" + esc(e[0]) + "' data-method='" + e[1] + "'><span class='method " + e[1] + "'>" +
+        e[1].toUpperCase() + "</span>" + esc(e[0]) + "</a>";
+    });
+  });
+  var nav = document.getElementById("nav");
+  nav.innerHTML = h;
+  nav.onclick = function (ev) {
+    var a = ev.target.closest("a");
+    if (a) { ev.preventDefault(); render(a.dataset.path, a.dataset.method); }
+  };
+  document.getElementById("main").innerHTML = "<h2>" + esc(doc.info.title) + " " + esc(doc.info.version) + "</h2>" +
+    "<p>" + esc(doc.info.description || "") + "</p><p><a href='openapi.json'>openapi.json</a></p>";
+});
+</script>
+</body>
+</html>
+`
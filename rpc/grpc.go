@@ -45,6 +45,7 @@ type GrpcConn struct {
 	conn *grpc.ClientConn
 	mutex *sync.RWMutex
 	client interface{}
+	closed bool
 
 	newClientFunc func(*grpc.ClientConn) interface{}
 
@@ -85,15 +86,22 @@ func (t *GrpcConn) Update(host string, port uint16, tlsCert string, macaroon str
 }
 
 func (t *GrpcConn) reopen() error {
-	if err := t.Close(); err != nil {
+	if err := t.closeConn(); err != nil {
 		return err
 	}
 	t.Open()
 	return nil
 }
 
+func (t *GrpcConn) isClosed() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.closed
+}
+
+// Open keeps trying to connect until it succeeds or the connection is closed
 func (t *GrpcConn) Open() {
-	for {
+	for !t.isClosed() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		err := t.connect(ctx)
 		cancel()
@@ -140,14 +148,26 @@ func (t *GrpcConn) connect(ctx context.Context) error {
 	}()
 
 	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.closed {
+		// closed while dialing
+		return conn.Close()
+	}
 	t.conn = conn
 	t.client = t.newClientFunc(conn)
-	t.mutex.Unlock()
 
 	return nil
 }
 
+// Close closes the connection for good and stops Open from reconnecting
 func (t *GrpcConn) Close() error {
+	t.mutex.Lock()
+	t.closed = true
+	t.mutex.Unlock()
+	return t.closeConn()
+}
+
+func (t *GrpcConn) closeConn() error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 	if t.conn != nil {
@@ -165,4 +185,11 @@ func (t *GrpcConn) GetClient() interface{} {
 	t.mutex.RLock()
 	defer t.mutex.RUnlock()
 	return t.client
-}
\ No newline at end of file
+}
+
+// GetConn returns the underlying connection or nil if it is not established
+func (t *GrpcConn) GetConn() *grpc.ClientConn {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.conn
+}
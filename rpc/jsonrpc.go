@@ -1,5 +1,241 @@
 package rpc
 
-type JsonrpcConn struct {
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/ybbus/jsonrpc"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+)
 
+// AllMethods in an allowlist forwards every method
+const AllMethods = "*"
+
+var (
+	errEmptyBatch = errors.New("empty batch")
+)
+
+// MethodNotAllowedError is returned by Check for a request (or a batch) calling a method missing in the allowlist
+type MethodNotAllowedError struct {
+	Method string
+}
+
+func (t *MethodNotAllowedError) Error() string {
+	return fmt.Sprintf("method not allowed: %s", t.Method)
+}
+
+// NodeError is returned by Forward when the node is unreachable or rejected the credentials of the proxy
+type NodeError struct {
+	Err error
+}
+
+func (t *NodeError) Error() string {
+	return t.Err.Error()
+}
+
+// JsonRpcClient calls the JSON-RPC API of a node over HTTP with a context and forwards raw requests restricted by an
+// allowlist of methods
+type JsonRpcClient struct {
+	url    string
+	client *http.Client
+	auth   JsonRpcAuth
+
+	// readOnly methods are allowed by default and need ScopeRead, the methods added by SetAllowed need ScopeAdmin
+	readOnly map[string]bool
+	allowed  map[string]bool
+	mutex    *sync.RWMutex
+}
+
+func toSet(methods []string) map[string]bool {
+	result := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		result[m] = true
+	}
+	return result
+}
+
+// NewJsonRpcClient creates a client of the node at url. The auth may be nil.
+func NewJsonRpcClient(url string, client *http.Client, auth JsonRpcAuth, readOnly []string) *JsonRpcClient {
+	return &JsonRpcClient{
+		url:      url,
+		client:   client,
+		auth:     auth,
+		readOnly: toSet(readOnly),
+		allowed:  toSet(readOnly),
+		mutex:    &sync.RWMutex{},
+	}
+}
+
+// Url returns the address of the node
+func (t *JsonRpcClient) Url() string {
+	return t.url
+}
+
+// SetAllowed replaces the methods which may be forwarded
+func (t *JsonRpcClient) SetAllowed(methods []string) {
+	allowed := toSet(methods)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.allowed = allowed
+}
+
+// Allowed returns the sorted methods which may be forwarded
+func (t *JsonRpcClient) Allowed() []string {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	result := []string{}
+	for m := range t.allowed {
+		result = append(result, m)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (t *JsonRpcClient) isAllowed(method string) bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.allowed[AllMethods] || t.allowed[method]
+}
+
+// post sends the body to the node. It is sent again when the node rejected the credentials and they changed in the
+// meantime (e.g. the cookie of a restarted bitcoind).
+func (t *JsonRpcClient) post(ctx context.Context, body []byte) (*http.Response, error) {
+	for retried := false; ; retried = true {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		if t.auth != nil {
+			if err := t.auth.Apply(req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := t.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusUnauthorized || t.auth == nil || retried || !t.auth.Reload() {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+	}
+}
+
+// Call calls a method of the node. Numbers in the result are decoded as json.Number.
+func (t *JsonRpcClient) Call(ctx context.Context, method string, params ...interface{}) (*jsonrpc.RPCResponse, error) {
+	request := jsonrpc.NewRequest(method, params...)
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.post(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("rpc call %s() on %s: %s", method, t.url, err)
+	}
+	defer resp.Body.Close()
+
+	var response *jsonrpc.RPCResponse
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&response); err != nil || response == nil {
+		// bitcoind answers RPC errors with a JSON body and status 500, anything else is a transport problem
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("rpc call %s() on %s status code: %d", method, t.url, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("rpc call %s() on %s: invalid response", method, t.url)
+	}
+	return response, nil
+}
+
+// CallFor calls a method of the node and decodes its result into out. Errors of the node are returned as
+// *jsonrpc.RPCError.
+func (t *JsonRpcClient) CallFor(ctx context.Context, out interface{}, method string, params ...interface{}) error {
+	resp, err := t.Call(ctx, method, params...)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if resp.Result == nil {
+		return fmt.Errorf("rpc call %s() on %s: empty result", method, t.url)
+	}
+	return resp.GetObject(out)
+}
+
+// rawRequest keeps the id and the params as sent by the caller
+type rawRequest struct {
+	JsonRpc string          `json:"jsonrpc,omitempty"`
+	Id      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Check parses a single or batch request and checks its methods against the allowlist. It returns the request
+// encoded again, which must be forwarded instead of the body: the node could otherwise read another method than the
+// checked one from duplicate or case-variant "method" keys. It also returns whether all the methods are read-only.
+func (t *JsonRpcClient) Check(body []byte) ([]byte, bool, error) {
+	body = bytes.TrimSpace(body)
+	var requests []rawRequest
+	batch := len(body) > 0 && body[0] == '['
+	if batch {
+		if err := json.Unmarshal(body, &requests); err != nil {
+			return nil, false, err
+		}
+		if len(requests) == 0 {
+			return nil, false, errEmptyBatch
+		}
+	} else {
+		var request rawRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			return nil, false, err
+		}
+		requests = append(requests, request)
+	}
+	readOnly := true
+	for _, r := range requests {
+		if !t.isAllowed(r.Method) {
+			return nil, false, &MethodNotAllowedError{Method: r.Method}
+		}
+		if !t.readOnly[r.Method] {
+			readOnly = false
+		}
+	}
+	var canonical []byte
+	var err error
+	if batch {
+		canonical, err = json.Marshal(requests)
+	} else {
+		canonical, err = json.Marshal(requests[0])
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return canonical, readOnly, nil
+}
+
+// Forward sends a JSON-RPC request (or batch) returned by Check to the node. It returns the HTTP status and the
+// body of the node's response, which carry the RPC errors.
+func (t *JsonRpcClient) Forward(ctx context.Context, body []byte) (int, []byte, error) {
+	resp, err := t.post(ctx, body)
+	if err != nil {
+		return 0, nil, &NodeError{Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		// never pass these through: the caller's credentials are fine, the proxy's are not
+		return 0, nil, &NodeError{Err: fmt.Errorf("the node rejected the RPC credentials of the proxy (status %d)", resp.StatusCode)}
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, &NodeError{Err: err}
+	}
+	return resp.StatusCode, data, nil
 }
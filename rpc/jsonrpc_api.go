@@ -0,0 +1,71 @@
+package rpc
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/auth"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	// maxJsonRpcBody limits the forwarded requests
+	maxJsonRpcBody = 1024 * 1024
+)
+
+// JsonRpcRequest documents a JSON-RPC request. A batch is an array of them.
+type JsonRpcRequest struct {
+	JsonRpc string        `json:"jsonrpc"`
+	Id      interface{}   `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type JsonRpcMethods struct {
+	Methods []string `json:"methods"`
+}
+
+func (t *JsonRpcClient) ConfigureSpec(spec *openapi.Spec, service string) {
+	spec.Route(http.MethodPost, fmt.Sprintf("/api/v1/%s/jsonrpc", service), "Forward a JSON-RPC request to the node").
+		Tag(service).
+		Describe("Forwards a single or batch JSON-RPC request if every method is in the allowlist of the service (read-only methods by default, see --jsonrpc-allow). Methods added to the allowlist require the admin scope. The request is forwarded with a single method key, the checked one. The response of the node is returned as is.").
+		Body(JsonRpcRequest{}).
+		Produces(http.StatusOK, "application/json", nil, "The JSON-RPC response of the node")
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/jsonrpc/methods", service), "List the JSON-RPC methods which may be forwarded").
+		Tag(service).
+		Returns(JsonRpcMethods{})
+}
+
+func (t *JsonRpcClient) ConfigureRouter(r *gin.RouterGroup, service string) {
+	r.POST(fmt.Sprintf("/v1/%s/jsonrpc", service), func(c *gin.Context) {
+		body, err := ioutil.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxJsonRpcBody))
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		request, readOnly, err := t.Check(body)
+		if err != nil {
+			if _, ok := err.(*MethodNotAllowedError); ok {
+				utils.JsonError(c, err.Error(), http.StatusForbidden)
+			} else {
+				utils.JsonError(c, fmt.Sprintf("invalid JSON-RPC request: %s", err), http.StatusBadRequest)
+			}
+			return
+		}
+		if identity := auth.GetIdentity(c); !readOnly && identity != nil && !identity.Role.Allows(auth.ScopeAdmin) {
+			utils.JsonError(c, fmt.Sprintf("permission denied: %s scope required", auth.ScopeAdmin), http.StatusForbidden)
+			return
+		}
+		status, data, err := t.Forward(c.Request.Context(), request)
+		if err != nil {
+			utils.JsonError(c, fmt.Sprintf("%s: %s", service, err), http.StatusBadGateway)
+			return
+		}
+		c.Data(status, "application/json", data)
+	})
+	r.GET(fmt.Sprintf("/v1/%s/jsonrpc/methods", service), func(c *gin.Context) {
+		c.JSON(http.StatusOK, JsonRpcMethods{Methods: t.Allowed()})
+	})
+}
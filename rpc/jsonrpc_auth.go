@@ -0,0 +1,89 @@
+package rpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// JsonRpcAuth adds credentials to the requests sent to a node
+type JsonRpcAuth interface {
+	Apply(r *http.Request) error
+	// Reload is called when the node rejected the credentials. It returns true if they changed and the request should
+	// be retried.
+	Reload() bool
+}
+
+func basicAuth(r *http.Request, credentials []byte) {
+	r.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString(credentials))
+}
+
+// BasicAuth sends a fixed user and password (rpcuser and rpcpassword of bitcoind)
+type BasicAuth struct {
+	User     string
+	Password string
+}
+
+func (t *BasicAuth) Apply(r *http.Request) error {
+	basicAuth(r, []byte(t.User+":"+t.Password))
+	return nil
+}
+
+func (t *BasicAuth) Reload() bool {
+	return false
+}
+
+// CookieAuth sends the content of the .cookie file which bitcoind rewrites on every start
+type CookieAuth struct {
+	file   string
+	cookie []byte
+	mutex  *sync.Mutex
+}
+
+func NewCookieAuth(file string) *CookieAuth {
+	return &CookieAuth{
+		file:  file,
+		mutex: &sync.Mutex{},
+	}
+}
+
+func (t *CookieAuth) read() ([]byte, error) {
+	data, err := ioutil.ReadFile(t.file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RPC cookie: %s", err)
+	}
+	// the file is "__cookie__:<password>"
+	data = bytes.TrimSpace(data)
+	if !bytes.Contains(data, []byte(":")) {
+		return nil, fmt.Errorf("invalid RPC cookie: %s", t.file)
+	}
+	return data, nil
+}
+
+func (t *CookieAuth) Apply(r *http.Request) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.cookie == nil {
+		cookie, err := t.read()
+		if err != nil {
+			return err
+		}
+		t.cookie = cookie
+	}
+	basicAuth(r, t.cookie)
+	return nil
+}
+
+func (t *CookieAuth) Reload() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	cookie, err := t.read()
+	if err != nil || bytes.Equal(cookie, t.cookie) {
+		return false
+	}
+	t.cookie = cookie
+	return true
+}
@@ -0,0 +1,58 @@
+package rpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckForwardsCheckedMethod(t *testing.T) {
+	var forwarded []byte
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded, _ = ioutil.ReadAll(r.Body)
+		_, _ = w.Write([]byte(`{"result":1,"error":null,"id":1}`))
+	}))
+	defer node.Close()
+	client := NewJsonRpcClient(node.URL, node.Client(), nil, []string{"getblockcount"})
+
+	bypasses := []string{
+		`{"method":"getblockcount","method":"stop"}`,
+		`{"method":"getblockcount","METHOD":"stop"}`,
+		`[{"id":1,"method":"getblockcount"},{"id":2,"method":"getblockcount","Method":"stop"}]`,
+	}
+	for _, body := range bypasses {
+		if _, _, err := client.Check([]byte(body)); err == nil {
+			t.Errorf("%s: stop passed the check", body)
+		}
+	}
+
+	cases := []struct {
+		body     string
+		expected string
+	}{
+		{`{"method":"stop","method":"getblockcount"}`, `{"method":"getblockcount"}`},
+		{`{"method":"stop","METHOD":"getblockcount","id":1}`, `{"id":1,"method":"getblockcount"}`},
+		{
+			`[{"jsonrpc":"2.0","id":"a","method":"stop","method":"getblockcount","params":[]},{"id":null,"Method":"getblockcount"}]`,
+			`[{"jsonrpc":"2.0","id":"a","method":"getblockcount","params":[]},{"id":null,"method":"getblockcount"}]`,
+		},
+	}
+	for _, c := range cases {
+		request, readOnly, err := client.Check([]byte(c.body))
+		if err != nil {
+			t.Errorf("%s: %s", c.body, err)
+			continue
+		}
+		if !readOnly {
+			t.Errorf("%s: not read-only", c.body)
+		}
+		if _, _, err := client.Forward(context.Background(), request); err != nil {
+			t.Fatal(err)
+		}
+		if string(forwarded) != c.expected {
+			t.Errorf("%s: forwarded %s, expected %s", c.body, forwarded, c.expected)
+		}
+	}
+}
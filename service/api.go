@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/build"
 	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/launcher"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/service/core"
 	"github.com/opendexnetwork/opendex-docker-api/utils"
 	"github.com/gin-gonic/contrib/static"
 	"github.com/gin-gonic/gin"
@@ -13,8 +16,51 @@ import (
 	"net/http"
 )
 
+func (t *Manager) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/version", "Get the version of the proxy").
+		Tag("proxy").Returns("")
+	spec.Route(http.MethodGet, "/api/v1/services", "List the services").
+		Tag("proxy").Returns([]ServiceEntry{})
+	spec.Route(http.MethodGet, "/api/v1/status", "Get the status of all services").
+		Tag("proxy").Returns([]ServiceStatus{})
+	spec.Route(http.MethodGet, "/api/v1/status/:service", "Get the status of a service").
+		Tag("proxy").PathParam("service", "e.g. opendexd").Returns(ServiceStatus{})
+	spec.Route(http.MethodGet, "/api/v1/sync", "Get the sync progress of the services").
+		Tag("proxy").
+		Describe("The rate is in blocks per second and smoothed over the samples taken every 15 seconds. The ETA is in seconds, -1 when unknown.").
+		Returns([]ServiceSync{})
+	spec.Route(http.MethodGet, "/api/v1/settings", "Get the settings of the services in config.json").
+		Tag("proxy").
+		Describe("Returns the disabled flag, the mode and the external node or provider settings of every service. Secrets are masked.").
+		Returns([]ServiceSettings{})
+	spec.Route(http.MethodGet, "/api/v1/settings/:service", "Get the settings of a service in config.json").
+		Tag("proxy").PathParam("service", "e.g. bitcoind").Returns(ServiceSettings{})
+	spec.Route(http.MethodPost, "/api/v1/settings/:service/preview", "Preview a change of the settings of a service").
+		Tag("proxy").PathParam("service", "e.g. bitcoind").
+		Describe("Validates the change and returns the differences and the services which would be recreated without changing config.json.").
+		Body(SettingsChange{}).Returns(SettingsPreview{})
+	spec.Route(http.MethodPut, "/api/v1/settings/:service", "Change the settings of a service").
+		Tag("proxy").PathParam("service", "e.g. bitcoind").
+		Describe("Writes the change to config.json and asks the attached launcher to recreate the affected containers. Returns 503 when config.json was written but the launcher failed.").
+		Body(SettingsChange{}).Returns(SettingsPreview{})
+	spec.Route(http.MethodGet, "/api/v1/logs/:service", "Download the logs of a service").
+		Tag("proxy").PathParam("service", "e.g. opendexd").
+		Query("since", "", "A duration or timestamp (default 1h)").
+		Query("tail", "", "The number of lines from the end (default all)").
+		Query("follow", false, "Keep streaming new lines until the client disconnects").
+		Produces(http.StatusOK, openapi.ContentTypeText, nil, "The log lines")
+	spec.Route(http.MethodGet, "/api/v1/setup-status", "Follow the setup progress of the launcher").
+		Tag("proxy").
+		Describe("Streams the status history and then every new status as newline delimited JSON until the status is \"Done\".").
+		Produces(http.StatusOK, openapi.ContentTypeNdjson, SetupStatus{}, "A stream of setup statuses")
+
+	for _, svc := range t.services {
+		svc.ConfigureSpec(spec)
+	}
+}
+
 func (t *Manager) ConfigureRouter(r *gin.Engine) {
-	r.Use(static.Serve("/", static.LocalFile("/ui", false)))
+	r.Use(static.Serve("/", static.LocalFile(t.config.UiDir, false)))
 
 	api := r.Group("/api")
 	{
@@ -44,7 +90,11 @@ func (t *Manager) ConfigureRouter(r *gin.Engine) {
 			var result []ServiceStatus
 
 			for _, svc := range t.services {
-				result = append(result, ServiceStatus{Service: svc.GetName(), Status: status[svc.GetName()]})
+				result = append(result, ServiceStatus{
+					Service: svc.GetName(),
+					Status:  status[svc.GetName()],
+					Sync:    t.GetSyncProgress(svc.GetName()),
+				})
 			}
 
 			c.JSON(http.StatusOK, result)
@@ -61,7 +111,64 @@ func (t *Manager) ConfigureRouter(r *gin.Engine) {
 			ctx, cancel := context.WithTimeout(ctx, config.DefaultApiTimeout)
 			defer cancel()
 			status := s.GetStatus(ctx)
-			c.JSON(http.StatusOK, ServiceStatus{Service: service, Status: status})
+			c.JSON(http.StatusOK, ServiceStatus{Service: service, Status: status, Sync: t.GetSyncProgress(service)})
+		})
+
+		api.GET("/v1/sync", func(c *gin.Context) {
+			c.JSON(http.StatusOK, t.GetSync())
+		})
+
+		api.GET("/v1/settings", func(c *gin.Context) {
+			resp, err := t.GetSettings()
+			if err != nil {
+				utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			c.JSON(http.StatusOK, resp)
+		})
+
+		api.GET("/v1/settings/:service", func(c *gin.Context) {
+			resp, err := t.GetServiceSettings(c.Param("service"))
+			if err != nil {
+				handleSettingsError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, resp)
+		})
+
+		api.POST("/v1/settings/:service/preview", func(c *gin.Context) {
+			var change SettingsChange
+			if err := c.BindJSON(&change); err != nil {
+				utils.JsonError(c, err.Error(), http.StatusBadRequest)
+				return
+			}
+			resp, err := t.PreviewSettings(c.Param("service"), change)
+			if err != nil {
+				handleSettingsError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, resp)
+		})
+
+		api.PUT("/v1/settings/:service", func(c *gin.Context) {
+			var change SettingsChange
+			if err := c.BindJSON(&change); err != nil {
+				utils.JsonError(c, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ctx, cancel := context.WithTimeout(c.Request.Context(), launcher.RequestTimeout)
+			defer cancel()
+			resp, err := t.ApplySettings(ctx, c.Param("service"), change)
+			if err != nil {
+				if resp != nil {
+					// config.json was written
+					utils.JsonError(c, err.Error(), http.StatusServiceUnavailable)
+					return
+				}
+				handleSettingsError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, resp)
 		})
 
 		api.GET("/v1/logs/:service", func(c *gin.Context) {
@@ -73,6 +180,10 @@ func (t *Manager) ConfigureRouter(r *gin.Engine) {
 			}
 			since := c.DefaultQuery("since", "1h")
 			tail := c.DefaultQuery("tail", "all")
+			if c.Query("follow") == "true" {
+				t.followLogs(c, s, since, tail)
+				return
+			}
 			logs, err := s.GetLogs(since, tail)
 			if err != nil {
 				utils.JsonError(c, err.Error(), http.StatusInternalServerError)
@@ -125,3 +236,50 @@ func (t *Manager) ConfigureRouter(r *gin.Engine) {
 		svc.ConfigureRouter(api)
 	}
 }
+
+func handleSettingsError(c *gin.Context, err error) {
+	if err == errSettingsNotFound {
+		utils.JsonError(c, err.Error(), http.StatusNotFound)
+	} else if _, ok := err.(*SettingsError); ok {
+		utils.JsonError(c, err.Error(), http.StatusBadRequest)
+	} else {
+		utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (t *Manager) followLogs(c *gin.Context, s core.Service, since string, tail string) {
+	lines, stop, err := s.FollowLogs(since, tail)
+	if err != nil {
+		utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		stop()
+		// FollowLogs blocks sending the remaining lines until they are consumed
+		go func() {
+			for range lines {
+			}
+		}()
+	}()
+
+	c.Header("Content-Type", "text/plain")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			if _, err := c.Writer.WriteString(line + "\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package bitcoind
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"github.com/ybbus/jsonrpc"
+	"net/http"
+)
+
+type RecentBlocksParams struct {
+	Count int `form:"count,default=10" json:"count" binding:"min=1"`
+}
+
+type TransactionParams struct {
+	BlockHash string `form:"blockhash" json:"blockhash"`
+}
+
+type FeeEstimateParams struct {
+	Target int32  `form:"target,default=6" json:"target" binding:"min=1,max=1008"`
+	Mode   string `form:"mode,default=CONSERVATIVE" json:"mode" binding:"oneof=UNSET ECONOMICAL CONSERVATIVE"`
+}
+
+// handleRpcResponse answers with the result or maps the error of the node
+func handleRpcResponse(c *gin.Context, result interface{}, err error) {
+	if err == nil {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	if rpcErr, ok := err.(*jsonrpc.RPCError); ok {
+		switch rpcErr.Code {
+		case rpcInvalidAddressOrKey:
+			utils.JsonError(c, rpcErr.Message, http.StatusNotFound)
+		case rpcInvalidParameter:
+			utils.JsonError(c, rpcErr.Message, http.StatusBadRequest)
+		case rpcInWarmup:
+			utils.JsonError(c, rpcErr.Message, http.StatusServiceUnavailable)
+		default:
+			utils.JsonError(c, rpcErr.Message, http.StatusInternalServerError)
+		}
+		return
+	}
+	utils.JsonError(c, err.Error(), http.StatusBadGateway)
+}
+
+// nativeOnly answers 409 unless the node is run by opendex-docker: in the light and external modes there is no node
+// to explore
+func (t *Service) nativeOnly(c *gin.Context) bool {
+	mode, err := t.getMode()
+	if err != nil {
+		utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if mode != Native {
+		utils.JsonError(c, fmt.Sprintf("not available in %s mode", mode), http.StatusConflict)
+		return false
+	}
+	return true
+}
+
+func (t *Service) ConfigureSpec(spec *openapi.Spec) {
+	name := t.GetName()
+	t.client.ConfigureSpec(spec, name)
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/external", name), "Probe the external node used by lnd").
+		Tag(name).
+		Describe("Calls getblockchaininfo on the node configured in lnd.conf (rpchost, rpcuser, rpcpass) and dials its ZMQ endpoints. Returns 409 when lnd doesn't use an external node.").
+		Returns(ExternalNode{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/blockchaininfo", name), "Get the state of the chain").
+		Tag(name).
+		Describe("The explorer routes return 409 when the node isn't run by opendex-docker (light or external mode).").
+		Returns(BlockchainInfo{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/networkinfo", name), "Get the P2P network state of the node").
+		Tag(name).Returns(NetworkInfo{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/peers", name), "List the connected peers").
+		Tag(name).Returns([]PeerInfo{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/mempool", name), "Get the size and fees of the mempool").
+		Tag(name).Returns(MempoolInfo{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/blocks", name), "List the headers of the last blocks").
+		Tag(name).
+		Describe(fmt.Sprintf("Returns the headers of the last blocks, the best block first (at most %d).", MaxRecentBlocks)).
+		QueryParams(RecentBlocksParams{}).Returns([]BlockHeader{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/blocks/:block", name), "Get a block").
+		Tag(name).PathParam("block", "The hash or height of the block").Returns(Block{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/blocks/:block/header", name), "Get a block header").
+		Tag(name).PathParam("block", "The hash or height of the block").Returns(BlockHeader{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/transactions/:txid", name), "Get a transaction").
+		Tag(name).PathParam("txid", "The transaction id").
+		Describe("Transactions which are not in the mempool require -txindex or the blockhash query parameter.").
+		QueryParams(TransactionParams{}).Returns(RawTransaction{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/estimatefee", name), "Estimate the fee rate").
+		Tag(name).
+		Describe("Estimates the fee rate (per kvB) for a confirmation within target blocks.").
+		QueryParams(FeeEstimateParams{}).Returns(FeeEstimate{})
+}
+
+func (t *Service) ConfigureRouter(r *gin.RouterGroup) {
+	name := t.GetName()
+	t.client.ConfigureRouter(r, name)
+
+	r.GET(fmt.Sprintf("/v1/%s/external", name), func(c *gin.Context) {
+		mode, err := t.getMode()
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if mode != External {
+			utils.JsonError(c, fmt.Sprintf("%s is in %s mode", name, mode), http.StatusConflict)
+			return
+		}
+		node, err := t.ProbeExternal(c.Request.Context())
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, node)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/blockchaininfo", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		resp, err := t.GetBlockchainInfo(c.Request.Context())
+		handleRpcResponse(c, resp, err)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/networkinfo", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		resp, err := t.GetNetworkInfo(c.Request.Context())
+		handleRpcResponse(c, resp, err)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/peers", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		resp, err := t.GetPeerInfo(c.Request.Context())
+		handleRpcResponse(c, resp, err)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/mempool", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		resp, err := t.GetMempoolInfo(c.Request.Context())
+		handleRpcResponse(c, resp, err)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/blocks", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		var params RecentBlocksParams
+		if err := c.BindQuery(&params); err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := t.GetRecentBlocks(c.Request.Context(), params.Count)
+		handleRpcResponse(c, resp, err)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/blocks/:block", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		resp, err := t.GetBlock(c.Request.Context(), c.Param("block"))
+		handleRpcResponse(c, resp, err)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/blocks/:block/header", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		resp, err := t.GetBlockHeader(c.Request.Context(), c.Param("block"))
+		handleRpcResponse(c, resp, err)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/transactions/:txid", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		var params TransactionParams
+		if err := c.BindQuery(&params); err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := t.GetRawTransaction(c.Request.Context(), c.Param("txid"), params.BlockHash)
+		handleRpcResponse(c, resp, err)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/estimatefee", name), func(c *gin.Context) {
+		if !t.nativeOnly(c) {
+			return
+		}
+		var params FeeEstimateParams
+		if err := c.BindQuery(&params); err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := t.EstimateSmartFee(c.Request.Context(), params.Target, params.Mode)
+		handleRpcResponse(c, resp, err)
+	})
+}
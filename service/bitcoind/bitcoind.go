@@ -2,19 +2,31 @@ package bitcoind
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/config"
 	"github.com/opendexnetwork/opendex-docker-api/service/core"
 	"github.com/opendexnetwork/opendex-docker-api/service/lnd"
 	docker "github.com/docker/docker/client"
+	"github.com/ybbus/jsonrpc"
+	"net/http"
+	"sync"
+	"time"
 )
 
 type Service struct {
 	*core.SingleContainerService
 	*RpcClient
 	l2ServiceName string
+	// resolvePath maps the paths of lnd.conf (e.g. rpccookie) to the network directory
+	resolvePath func(path string) string
+
+	// externalClient is shared by the probes of the external node, whose last result is cached for the status and
+	// the sync tracker
+	externalClient  *http.Client
+	externalMutex   *sync.Mutex
+	externalNode    *ExternalNode
+	externalProbeAt time.Time
 }
 
 type Mode string
@@ -32,11 +44,15 @@ func New(
 	dockerClient *docker.Client,
 	l2ServiceName string,
 	rpcConfig config.RpcConfig,
+	resolvePath func(path string) string,
 ) *Service {
 	return &Service{
 		SingleContainerService: core.NewSingleContainerService(name, services, containerName, dockerClient),
 		RpcClient:              NewRpcClient(rpcConfig),
 		l2ServiceName:          l2ServiceName,
+		resolvePath:            resolvePath,
+		externalClient:         &http.Client{Timeout: HttpRequestTimeout},
+		externalMutex:          &sync.Mutex{},
 	}
 }
 
@@ -54,27 +70,30 @@ func (t *Service) getMode() (Mode, error) {
 	if err != nil {
 		return "", err
 	}
-	backend, err := lndSvc.GetBackendNode()
+	cfg, err := lndSvc.GetConfig()
 	if err != nil {
 		return "", err
 	}
-	if backend == "bitcoind" || backend == "litecoind" {
+	switch cfg.Backend {
+	case lnd.BackendBitcoind, lnd.BackendLitecoind:
 		// could be native or external
-		values, err := lndSvc.GetConfigValues(fmt.Sprintf("%s.rpchost", backend))
-		if err != nil {
-			return "", err
-		}
-		host := values[0]
-		if host == backend {
+		if cfg.RpcHost == cfg.Backend {
 			return Native, nil
-		} else {
-			return External, nil
 		}
-	} else if backend == "neutrino" {
+		return External, nil
+	case lnd.BackendNeutrino:
 		return Light, nil
-	} else {
-		return "", errors.New("unexpected backend: " + backend)
+	default:
+		return "", errors.New("unexpected backend: " + cfg.Backend)
+	}
+}
+
+func syncingText(current int64, total int64) string {
+	if total == 0 {
+		return "0.00% (0/0)"
 	}
+	p := float32(current) / float32(total) * 100.0
+	return fmt.Sprintf("%.2f%% (%d/%d)", p, current, total)
 }
 
 func (t *Service) GetStatus(ctx context.Context) string {
@@ -93,36 +112,23 @@ func (t *Service) GetStatus(ctx context.Context) string {
 		}
 
 		// container is running
-		resp, err := t.GetBlockchainInfo(ctx)
+		info, err := t.GetBlockchainInfo(ctx)
 		if err != nil {
+			if rpcErr, ok := err.(*jsonrpc.RPCError); ok {
+				// Loading block index...
+				return rpcErr.Message
+			}
 			return fmt.Sprintf("Waiting for %s to come up...", t.GetName())
 		}
-		if resp.Error != nil {
-			// Loading block index...
-			return resp.Error.Message
-		}
-		r := resp.Result.(map[string]interface{})
-		current, err := r["blocks"].(json.Number).Int64()
-		if err != nil {
-			return fmt.Sprintf("Error: %s", err)
-		}
-		total, err := r["headers"].(json.Number).Int64()
-		if err != nil {
-			return fmt.Sprintf("Error: %s", err)
-		}
+		current := int64(info.Blocks)
+		total := int64(info.Headers)
 		if current > 0 && current == total {
 			return "Ready"
 		} else {
-			if total == 0 {
-				return "Syncing 0.00% (0/0)"
-			} else {
-				p := float32(current) / float32(total) * 100.0
-				return fmt.Sprintf("Syncing %.2f%% (%d/%d)", p, current, total)
-			}
+			return fmt.Sprintf("Syncing %s", syncingText(current, total))
 		}
 	case External:
-		// TODO Unavailable (connection to external failed)
-		return "Ready (connected to external)"
+		return t.externalStatus(ctx)
 	case Light:
 		return "Ready (light mode)"
 	default:
@@ -130,6 +136,33 @@ func (t *Service) GetStatus(ctx context.Context) string {
 	}
 }
 
+// GetSyncHeights returns the blocks and headers of the node of the native or external mode
+func (t *Service) GetSyncHeights(ctx context.Context) (int64, int64, error) {
+	mode, err := t.getMode()
+	if err != nil {
+		return 0, 0, err
+	}
+	switch mode {
+	case Native:
+		info, err := t.GetBlockchainInfo(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		return int64(info.Blocks), int64(info.Headers), nil
+	case External:
+		node, err := t.probeExternalCached(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		if node.Error != "" {
+			return 0, 0, errors.New(node.Error)
+		}
+		return node.Blocks, node.Headers, nil
+	default:
+		return 0, 0, core.ErrNotSyncing
+	}
+}
+
 func (t *Service) Close() error {
 	err := t.RpcClient.Close()
 	if err != nil {
@@ -0,0 +1,270 @@
+package bitcoind
+
+import (
+	"context"
+	"github.com/ybbus/jsonrpc"
+	"strconv"
+)
+
+const (
+	// MaxRecentBlocks limits GetRecentBlocks
+	MaxRecentBlocks = 50
+)
+
+// RPC error codes of bitcoind
+const (
+	rpcInvalidAddressOrKey = -5
+	rpcInvalidParameter    = -8
+	rpcInWarmup            = -28
+)
+
+type NetworkAddress struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	Score   int    `json:"score"`
+}
+
+type Network struct {
+	Name      string `json:"name"`
+	Limited   bool   `json:"limited"`
+	Reachable bool   `json:"reachable"`
+	Proxy     string `json:"proxy"`
+}
+
+type NetworkInfo struct {
+	Version         int32            `json:"version"`
+	SubVersion      string           `json:"subversion"`
+	ProtocolVersion int32            `json:"protocolversion"`
+	LocalServices   string           `json:"localservices"`
+	LocalRelay      bool             `json:"localrelay"`
+	TimeOffset      int64            `json:"timeoffset"`
+	NetworkActive   bool             `json:"networkactive"`
+	Connections     int32            `json:"connections"`
+	Networks        []Network        `json:"networks"`
+	RelayFee        float64          `json:"relayfee"`
+	IncrementalFee  float64          `json:"incrementalfee"`
+	LocalAddresses  []NetworkAddress `json:"localaddresses"`
+	Warnings        string           `json:"warnings"`
+}
+
+type PeerInfo struct {
+	Id             int32   `json:"id"`
+	Addr           string  `json:"addr"`
+	AddrLocal      string  `json:"addrlocal,omitempty"`
+	Services       string  `json:"services"`
+	RelayTxes      bool    `json:"relaytxes"`
+	LastSend       int64   `json:"lastsend"`
+	LastRecv       int64   `json:"lastrecv"`
+	BytesSent      int64   `json:"bytessent"`
+	BytesRecv      int64   `json:"bytesrecv"`
+	ConnTime       int64   `json:"conntime"`
+	TimeOffset     int64   `json:"timeoffset"`
+	PingTime       float64 `json:"pingtime"`
+	Version        int32   `json:"version"`
+	SubVer         string  `json:"subver"`
+	Inbound        bool    `json:"inbound"`
+	StartingHeight int32   `json:"startingheight"`
+	SyncedHeaders  int32   `json:"synced_headers"`
+	SyncedBlocks   int32   `json:"synced_blocks"`
+}
+
+type MempoolInfo struct {
+	Loaded        bool    `json:"loaded"`
+	Size          int64   `json:"size"`
+	Bytes         int64   `json:"bytes"`
+	Usage         int64   `json:"usage"`
+	MaxMempool    int64   `json:"maxmempool"`
+	MempoolMinFee float64 `json:"mempoolminfee"`
+	MinRelayTxFee float64 `json:"minrelaytxfee"`
+}
+
+type BlockHeader struct {
+	Hash              string  `json:"hash"`
+	Confirmations     int64   `json:"confirmations"`
+	Height            int32   `json:"height"`
+	Version           int32   `json:"version"`
+	MerkleRoot        string  `json:"merkleroot"`
+	Time              int64   `json:"time"`
+	MedianTime        int64   `json:"mediantime"`
+	Nonce             uint32  `json:"nonce"`
+	Bits              string  `json:"bits"`
+	Difficulty        float64 `json:"difficulty"`
+	ChainWork         string  `json:"chainwork"`
+	NTx               int32   `json:"nTx"`
+	PreviousBlockHash string  `json:"previousblockhash,omitempty"`
+	NextBlockHash     string  `json:"nextblockhash,omitempty"`
+}
+
+// Block is the result of getblock with verbosity 1, the transactions are their ids
+type Block struct {
+	BlockHeader
+	StrippedSize int32    `json:"strippedsize"`
+	Size         int32    `json:"size"`
+	Weight       int32    `json:"weight"`
+	Tx           []string `json:"tx"`
+}
+
+type ScriptSig struct {
+	Asm string `json:"asm"`
+	Hex string `json:"hex"`
+}
+
+type TxIn struct {
+	Coinbase    string    `json:"coinbase,omitempty"`
+	TxId        string    `json:"txid,omitempty"`
+	Vout        uint32    `json:"vout"`
+	ScriptSig   ScriptSig `json:"scriptSig"`
+	TxInWitness []string  `json:"txinwitness,omitempty"`
+	Sequence    uint32    `json:"sequence"`
+}
+
+type ScriptPubKey struct {
+	Asm       string   `json:"asm"`
+	Hex       string   `json:"hex"`
+	ReqSigs   int32    `json:"reqSigs,omitempty"`
+	Type      string   `json:"type"`
+	Address   string   `json:"address,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+type TxOut struct {
+	Value        float64      `json:"value"`
+	N            uint32       `json:"n"`
+	ScriptPubKey ScriptPubKey `json:"scriptPubKey"`
+}
+
+// RawTransaction is the result of getrawtransaction with verbose set
+type RawTransaction struct {
+	TxId          string  `json:"txid"`
+	Hash          string  `json:"hash"`
+	Version       int32   `json:"version"`
+	Size          int32   `json:"size"`
+	VSize         int32   `json:"vsize"`
+	Weight        int32   `json:"weight"`
+	LockTime      uint32  `json:"locktime"`
+	Vin           []TxIn  `json:"vin"`
+	Vout          []TxOut `json:"vout"`
+	Hex           string  `json:"hex"`
+	BlockHash     string  `json:"blockhash,omitempty"`
+	Confirmations int64   `json:"confirmations,omitempty"`
+	Time          int64   `json:"time,omitempty"`
+	BlockTime     int64   `json:"blocktime,omitempty"`
+}
+
+// FeeEstimate is the result of estimatesmartfee. The fee rate is in BTC (or LTC) per kvB.
+type FeeEstimate struct {
+	FeeRate float64  `json:"feerate,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+	Blocks  int32    `json:"blocks"`
+}
+
+func (t *RpcClient) GetNetworkInfo(ctx context.Context) (*NetworkInfo, error) {
+	var result NetworkInfo
+	if err := t.client.CallFor(ctx, &result, "getnetworkinfo"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (t *RpcClient) GetPeerInfo(ctx context.Context) ([]PeerInfo, error) {
+	result := []PeerInfo{}
+	if err := t.client.CallFor(ctx, &result, "getpeerinfo"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (t *RpcClient) GetMempoolInfo(ctx context.Context) (*MempoolInfo, error) {
+	var result MempoolInfo
+	if err := t.client.CallFor(ctx, &result, "getmempoolinfo"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// resolveBlock returns the hash of a block given by its hash or height
+func (t *RpcClient) resolveBlock(ctx context.Context, id string) (string, error) {
+	height, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return id, nil
+	}
+	var hash string
+	if err := t.client.CallFor(ctx, &hash, "getblockhash", height); err != nil {
+		if rpcErr, ok := err.(*jsonrpc.RPCError); ok && rpcErr.Code == rpcInvalidParameter {
+			// a height above the tip is a missing block like an unknown hash
+			return "", &jsonrpc.RPCError{Code: rpcInvalidAddressOrKey, Message: rpcErr.Message}
+		}
+		return "", err
+	}
+	return hash, nil
+}
+
+// GetBlock returns a block by its hash or height
+func (t *RpcClient) GetBlock(ctx context.Context, id string) (*Block, error) {
+	hash, err := t.resolveBlock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var result Block
+	if err := t.client.CallFor(ctx, &result, "getblock", hash, 1); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBlockHeader returns a block header by its hash or height
+func (t *RpcClient) GetBlockHeader(ctx context.Context, id string) (*BlockHeader, error) {
+	hash, err := t.resolveBlock(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var result BlockHeader
+	if err := t.client.CallFor(ctx, &result, "getblockheader", hash, true); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRecentBlocks returns the headers of the last blocks, the best block first
+func (t *RpcClient) GetRecentBlocks(ctx context.Context, count int) ([]BlockHeader, error) {
+	if count > MaxRecentBlocks {
+		count = MaxRecentBlocks
+	}
+	var hash string
+	if err := t.client.CallFor(ctx, &hash, "getbestblockhash"); err != nil {
+		return nil, err
+	}
+	result := []BlockHeader{}
+	for len(result) < count && hash != "" {
+		header, err := t.GetBlockHeader(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *header)
+		hash = header.PreviousBlockHash
+	}
+	return result, nil
+}
+
+// GetRawTransaction returns a transaction of the mempool or, with -txindex or the hash of its block, of the chain
+func (t *RpcClient) GetRawTransaction(ctx context.Context, txId string, blockHash string) (*RawTransaction, error) {
+	params := []interface{}{txId, true}
+	if blockHash != "" {
+		params = append(params, blockHash)
+	}
+	var result RawTransaction
+	if err := t.client.CallFor(ctx, &result, "getrawtransaction", params...); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// EstimateSmartFee estimates the fee rate for a confirmation within target blocks. The mode is UNSET, ECONOMICAL or
+// CONSERVATIVE.
+func (t *RpcClient) EstimateSmartFee(ctx context.Context, target int32, mode string) (*FeeEstimate, error) {
+	var result FeeEstimate
+	if err := t.client.CallFor(ctx, &result, "estimatesmartfee", target, mode); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
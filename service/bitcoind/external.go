@@ -0,0 +1,232 @@
+package bitcoind
+
+import (
+	"context"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker-api/rpc"
+	"github.com/opendexnetwork/opendex-docker-api/service/lnd"
+	"github.com/ybbus/jsonrpc"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var (
+	ZmqDialTimeout = 2 * time.Second
+	// ExternalProbeTtl is how long GetStatus and GetSyncHeights reuse the result of ProbeExternal
+	ExternalProbeTtl = 10 * time.Second
+
+	// defaultRpcPorts of bitcoind and litecoind by network, used when rpchost has no port
+	defaultRpcPorts = map[string]map[string]string{
+		"bitcoind":  {"main": "8332", "test": "18332", "regtest": "18443"},
+		"litecoind": {"main": "9332", "test": "19332", "regtest": "19443"},
+	}
+
+	// lndNetworks maps the network options of lnd.conf to the chain names of getblockchaininfo
+	lndNetworks = map[string]string{
+		"mainnet": "main",
+		"testnet": "test",
+		"regtest": "regtest",
+		"simnet":  "regtest",
+	}
+
+	zmqKeys = []string{"zmqpubrawblock", "zmqpubrawtx"}
+)
+
+type ZmqEndpoint struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ExternalNode is the state of the external node which lnd is configured to use
+type ExternalNode struct {
+	Host string `json:"host"`
+	// Chain is the chain of the node (main, test or regtest) and ExpectedChain the one lnd is configured for
+	Chain                string        `json:"chain"`
+	ExpectedChain        string        `json:"expectedChain"`
+	Blocks               int64         `json:"blocks"`
+	Headers              int64         `json:"headers"`
+	VerificationProgress float64       `json:"verificationProgress"`
+	InitialBlockDownload bool          `json:"initialBlockDownload"`
+	Latency              int64         `json:"latency"`
+	Zmq                  []ZmqEndpoint `json:"zmq"`
+	Error                string        `json:"error,omitempty"`
+}
+
+type externalConfig struct {
+	host          string
+	auth          rpc.JsonRpcAuth
+	zmq           map[string]string
+	expectedChain string
+}
+
+// lndConfigValue returns the value of a key of lnd.conf or "" if it is missing
+func (t *Service) lndConfigValue(key string) string {
+	lndSvc, err := t.getL2Service()
+	if err != nil {
+		return ""
+	}
+	value, err := lndSvc.GetConfigValue(key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+func (t *Service) loadExternalConfig(lndConfig *lnd.Config) (*externalConfig, error) {
+	backend := lndConfig.Backend
+	result := &externalConfig{
+		expectedChain: lndNetworks[lndConfig.Network],
+		zmq: map[string]string{
+			"zmqpubrawblock": lndConfig.ZmqPubRawBlock,
+			"zmqpubrawtx":    lndConfig.ZmqPubRawTx,
+		},
+	}
+
+	host := lndConfig.RpcHost
+	if host == "" {
+		return nil, fmt.Errorf("%s.rpchost is not set in the lnd config", backend)
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		port := defaultRpcPorts[backend][result.expectedChain]
+		if port == "" {
+			port = defaultRpcPorts[backend]["main"]
+		}
+		host = net.JoinHostPort(host, port)
+	}
+	result.host = host
+
+	// the credentials aren't part of lnd.Config which the API exposes
+	if cookie := t.lndConfigValue(backend + ".rpccookie"); cookie != "" {
+		result.auth = rpc.NewCookieAuth(t.resolvePath(cookie))
+	} else {
+		result.auth = &rpc.BasicAuth{
+			User:     t.lndConfigValue(backend + ".rpcuser"),
+			Password: t.lndConfigValue(backend + ".rpcpass"),
+		}
+	}
+	return result, nil
+}
+
+func checkZmq(ctx context.Context, name string, value string) ZmqEndpoint {
+	e := ZmqEndpoint{Name: name, Address: value}
+	if value == "" {
+		e.Error = "not configured"
+		return e
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme != "tcp" || u.Host == "" {
+		e.Error = "not a tcp://host:port address"
+		return e
+	}
+	dialer := net.Dialer{Timeout: ZmqDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		e.Error = err.Error()
+		return e
+	}
+	_ = conn.Close()
+	e.Reachable = true
+	return e
+}
+
+// ProbeExternal calls getblockchaininfo on the external node configured in lnd.conf and dials its ZMQ endpoints.
+// Problems of the node are reported in ExternalNode.Error; the error is returned when lnd.conf can't be read.
+func (t *Service) ProbeExternal(ctx context.Context) (*ExternalNode, error) {
+	lndSvc, err := t.getL2Service()
+	if err != nil {
+		return nil, err
+	}
+	lndConfig, err := lndSvc.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := t.loadExternalConfig(lndConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExternalNode{
+		Host:          cfg.host,
+		ExpectedChain: cfg.expectedChain,
+		Zmq:           make([]ZmqEndpoint, len(zmqKeys)),
+	}
+
+	var wg sync.WaitGroup
+	for i, key := range zmqKeys {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			result.Zmq[i] = checkZmq(ctx, key, cfg.zmq[key])
+		}(i, key)
+	}
+
+	client := rpc.NewJsonRpcClient("http://"+cfg.host, t.externalClient, cfg.auth, nil)
+	var info BlockchainInfo
+	start := time.Now()
+	err = client.CallFor(ctx, &info, "getblockchaininfo")
+	result.Latency = time.Since(start).Milliseconds()
+	wg.Wait()
+
+	if err != nil {
+		if rpcErr, ok := err.(*jsonrpc.RPCError); ok {
+			result.Error = rpcErr.Message
+		} else {
+			result.Error = err.Error()
+		}
+	} else {
+		result.Chain = info.Chain
+		result.Blocks = int64(info.Blocks)
+		result.Headers = int64(info.Headers)
+		result.VerificationProgress = info.VerificationProgress
+		result.InitialBlockDownload = info.InitialBlockDownload
+		if result.ExpectedChain != "" && result.Chain != result.ExpectedChain {
+			result.Error = fmt.Sprintf("the node is on %s but lnd expects %s", result.Chain, result.ExpectedChain)
+		}
+	}
+
+	t.externalMutex.Lock()
+	t.externalNode = result
+	t.externalProbeAt = time.Now()
+	t.externalMutex.Unlock()
+	return result, nil
+}
+
+// probeExternalCached returns the result of the last probe if it is more recent than ExternalProbeTtl, since the
+// status and the sync tracker poll it
+func (t *Service) probeExternalCached(ctx context.Context) (*ExternalNode, error) {
+	t.externalMutex.Lock()
+	node := t.externalNode
+	fresh := time.Since(t.externalProbeAt) < ExternalProbeTtl
+	t.externalMutex.Unlock()
+	if node != nil && fresh {
+		return node, nil
+	}
+	return t.ProbeExternal(ctx)
+}
+
+// externalStatus summarizes ProbeExternal for GetStatus
+func (t *Service) externalStatus(ctx context.Context) string {
+	node, err := t.probeExternalCached(ctx)
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err)
+	}
+	if node.Error != "" {
+		if node.Chain == "" {
+			return "Unavailable (connection to external failed)"
+		}
+		return fmt.Sprintf("Error: %s", node.Error)
+	}
+	for _, e := range node.Zmq {
+		if !e.Reachable {
+			return fmt.Sprintf("Unavailable (external %s %s unreachable)", e.Name, e.Address)
+		}
+	}
+	if node.Blocks == 0 || node.Blocks < node.Headers {
+		return fmt.Sprintf("Syncing %s (external)", syncingText(node.Blocks, node.Headers))
+	}
+	return fmt.Sprintf("Ready (connected to external, %dms)", node.Latency)
+}
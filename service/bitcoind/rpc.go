@@ -2,11 +2,10 @@ package bitcoind
 
 import (
 	"context"
-	"encoding/base64"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/config"
-	"github.com/ybbus/jsonrpc"
+	"github.com/opendexnetwork/opendex-docker-api/rpc"
 	"net/http"
 	"time"
 )
@@ -15,30 +14,97 @@ var (
 	HttpRequestTimeout = 3 * time.Second
 )
 
+const (
+	// DefaultUser and DefaultPassword are the credentials of the bitcoind and litecoind containers of opendex-docker
+	DefaultUser     = "xu"
+	DefaultPassword = "xu"
+)
+
 type Fork struct {
-	Type   string
-	Active bool
-	Height int32
+	Type   string `json:"type"`
+	Active bool   `json:"active"`
+	Height int32  `json:"height,omitempty"`
+}
+
+// SoftForks accepts the map of bitcoind 0.19+ and the array of older nodes (e.g. litecoind 0.18)
+type SoftForks map[string]Fork
+
+func (t *SoftForks) UnmarshalJSON(data []byte) error {
+	var forks map[string]Fork
+	if err := json.Unmarshal(data, &forks); err == nil {
+		*t = forks
+		return nil
+	}
+	var list []struct {
+		Id     string `json:"id"`
+		Reject struct {
+			Status bool `json:"status"`
+		} `json:"reject"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = SoftForks{}
+	for _, f := range list {
+		(*t)[f.Id] = Fork{Type: "buried", Active: f.Reject.Status}
+	}
+	return nil
 }
 
 type BlockchainInfo struct {
-	Chain                string
-	Blocks               int32
-	Headers              int32
-	BestBlockHash        string
-	Difficulty           float64
-	MedianTime           int32
-	VerificationProgress float32
-	InitialBlockDownload bool
-	ChainWork            string
-	SizeOnDisk           int32
-	Pruned               bool
-	SoftForks            map[string]Fork
-	Warnings             string
+	Chain                string    `json:"chain"`
+	Blocks               int32     `json:"blocks"`
+	Headers              int32     `json:"headers"`
+	BestBlockHash        string    `json:"bestblockhash"`
+	Difficulty           float64   `json:"difficulty"`
+	MedianTime           int32     `json:"mediantime"`
+	VerificationProgress float64   `json:"verificationprogress"`
+	InitialBlockDownload bool      `json:"initialblockdownload"`
+	ChainWork            string    `json:"chainwork"`
+	SizeOnDisk           int64     `json:"size_on_disk"`
+	Pruned               bool      `json:"pruned"`
+	SoftForks            SoftForks `json:"softforks,omitempty"`
+	Warnings             string    `json:"warnings"`
+}
+
+// ReadOnlyMethods are forwarded by /api/v1/<service>/jsonrpc by default. They are the same for litecoind.
+var ReadOnlyMethods = []string{
+	"decoderawtransaction",
+	"decodescript",
+	"estimatesmartfee",
+	"getbestblockhash",
+	"getblock",
+	"getblockchaininfo",
+	"getblockcount",
+	"getblockfilter",
+	"getblockhash",
+	"getblockheader",
+	"getblockstats",
+	"getchaintips",
+	"getchaintxstats",
+	"getconnectioncount",
+	"getdifficulty",
+	"getmempoolancestors",
+	"getmempooldescendants",
+	"getmempoolentry",
+	"getmempoolinfo",
+	"getmininginfo",
+	"getnettotals",
+	"getnetworkhashps",
+	"getnetworkinfo",
+	"getpeerinfo",
+	"getrawmempool",
+	"getrawtransaction",
+	"gettxout",
+	"gettxoutproof",
+	"getzmqnotifications",
+	"uptime",
+	"validateaddress",
+	"verifytxoutproof",
 }
 
 type RpcClient struct {
-	client jsonrpc.RPCClient
+	client *rpc.JsonRpcClient
 }
 
 func NewRpcClient(config config.RpcConfig) *RpcClient {
@@ -46,34 +112,43 @@ func NewRpcClient(config config.RpcConfig) *RpcClient {
 	port := uint16(config["port"].(float64))
 
 	addr := fmt.Sprintf("http://%s:%d", host, port)
-	client := jsonrpc.NewClientWithOpts(addr, &jsonrpc.RPCClientOpts{
-		HTTPClient: &http.Client{
-			Timeout: HttpRequestTimeout,
-		},
-		CustomHeaders: map[string]string{
-			"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte("xu"+":"+"xu")),
-		},
-	})
+	httpClient := &http.Client{
+		Timeout: HttpRequestTimeout,
+	}
 
 	return &RpcClient{
-		client: client,
+		client: rpc.NewJsonRpcClient(addr, httpClient, newAuth(config), ReadOnlyMethods),
 	}
 }
 
+// newAuth takes the credentials from the "cookie" file or the "username" and "password" of the RPC config. The
+// cookie is re-read when bitcoind rejects it.
+func newAuth(config config.RpcConfig) rpc.JsonRpcAuth {
+	if cookie, ok := config["cookie"].(string); ok && cookie != "" {
+		return rpc.NewCookieAuth(cookie)
+	}
+	user, _ := config["username"].(string)
+	password, _ := config["password"].(string)
+	if user == "" && password == "" {
+		user = DefaultUser
+		password = DefaultPassword
+	}
+	return &rpc.BasicAuth{User: user, Password: password}
+}
+
 func (t *RpcClient) Close() error {
 	return nil
 }
 
-func (t *RpcClient) GetBlockchainInfo(ctx context.Context) (*jsonrpc.RPCResponse, error) {
-	// TODO jsonrpc call with context
-	select {
-	case <-ctx.Done():
-		return nil, errors.New("cancelled by context")
-	default:
-		response, err := t.client.Call("getblockchaininfo")
-		if err != nil {
-			return nil, err
-		}
-		return response, nil
+// GetJsonRpcClient returns the client of the JSON-RPC passthrough
+func (t *RpcClient) GetJsonRpcClient() *rpc.JsonRpcClient {
+	return t.client
+}
+
+func (t *RpcClient) GetBlockchainInfo(ctx context.Context) (*BlockchainInfo, error) {
+	var result BlockchainInfo
+	if err := t.client.CallFor(ctx, &result, "getblockchaininfo"); err != nil {
+		return nil, err
 	}
+	return &result, nil
 }
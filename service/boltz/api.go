@@ -4,12 +4,30 @@ import (
 	"context"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	pb "github.com/opendexnetwork/opendex-docker-api/service/boltz/boltzrpc"
 	"github.com/opendexnetwork/opendex-docker-api/utils"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
 )
 
+type WithdrawParams struct {
+	Amount  int64  `form:"amount"`
+	Address string `form:"address"`
+}
+
+func (t *Service) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/boltz/service-info/:currency", "Get the limits and fees of Boltz").
+		Tag("boltz").PathParam("currency", "btc or ltc").Returns(&pb.GetServiceInfoResponse{})
+	spec.Route(http.MethodGet, "/api/v1/boltz/deposit/:currency", "Get an address to deposit into a channel").
+		Tag("boltz").PathParam("currency", "btc or ltc").
+		Query("inbound_liquidity", uint32(0), "The percentage of inbound liquidity (default 50)").
+		Returns(&pb.DepositResponse{})
+	spec.Route(http.MethodPost, "/api/v1/boltz/withdraw/:currency", "Withdraw from a channel with a reverse swap").
+		Tag("boltz").PathParam("currency", "btc or ltc").Form(WithdrawParams{}).Returns(&pb.CreateReverseSwapResponse{})
+}
+
 func (t *Service) ConfigureRouter(r *gin.RouterGroup) {
 	r.GET("/v1/boltz/service-info/:currency", func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), config.DefaultApiTimeout)
@@ -115,3 +115,8 @@ func (t *RpcClient) Withdraw(ctx context.Context, currency string, amount int64,
 	req.Address = address
 	return client.CreateReverseSwap(ctx, &req)
 }
+
+// GrpcConns returns the connections of the service by chain for the gRPC gateway
+func (t *RpcClient) GrpcConns() map[string]*rpc.GrpcConn {
+	return map[string]*rpc.GrpcConn{"bitcoin": t.btcConn, "litecoin": t.ltcConn}
+}
@@ -0,0 +1,126 @@
+package connext
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"net/http"
+)
+
+type Chains struct {
+	Network string `json:"network"`
+	// Chain is the chain the connext node of the network must have a provider for
+	Chain     Chain           `json:"chain"`
+	Providers []ChainProvider `json:"providers"`
+}
+
+type DepositParams struct {
+	ChannelAddress string `json:"channelAddress" binding:"required"`
+	AssetId        string `json:"assetId" binding:"required"`
+}
+
+// handleVectorResponse answers with the result or the error of the vector node
+func handleVectorResponse(c *gin.Context, result interface{}, err error) {
+	if err == nil {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	if e, ok := err.(*VectorError); ok && e.Status >= 400 && e.Status < 500 {
+		utils.JsonError(c, e.Message, e.Status)
+		return
+	}
+	utils.JsonError(c, err.Error(), http.StatusBadGateway)
+}
+
+func (t *Service) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/connext/chains", "List the chain providers of connext").
+		Tag("connext").
+		Describe("Returns the chainProviders of VECTOR_CONFIG and the chain expected for the network of the proxy.").
+		Returns(Chains{})
+	spec.Route(http.MethodGet, "/api/v1/connext/config", "Get the signers of the vector node").
+		Tag("connext").Returns([]NodeConfig{})
+	spec.Route(http.MethodGet, "/api/v1/connext/channels", "List the channels").
+		Tag("connext").Returns([]ChannelState{})
+	spec.Route(http.MethodGet, "/api/v1/connext/channels/:address", "Get the state of a channel").
+		Tag("connext").PathParam("address", "The channel address").Returns(ChannelState{})
+	spec.Route(http.MethodGet, "/api/v1/connext/channels/:address/balances", "Get the balances of a channel per asset").
+		Tag("connext").PathParam("address", "The channel address").
+		Describe("Returns the local and remote balance (in wei) of every asset of the channel.").
+		Returns([]AssetBalance{})
+	spec.Route(http.MethodGet, "/api/v1/connext/transfers", "List the transfers").
+		Tag("connext").
+		Describe("Returns the transfer history of the node, optionally of a channel. active=true returns the unresolved transfers of the channel.").
+		QueryParams(TransferFilter{}).Returns([]Transfer{})
+	spec.Route(http.MethodPost, "/api/v1/connext/deposit", "Reconcile a deposit").
+		Tag("connext").
+		Describe("Credits the on-chain deposits of an asset sent to the channel address to the channel balance.").
+		Body(DepositParams{}).Returns(DepositResult{})
+	spec.Route(http.MethodPost, "/api/v1/connext/withdraw", "Withdraw from a channel").
+		Tag("connext").Body(WithdrawParams{}).Returns(WithdrawResult{})
+}
+
+func (t *Service) ConfigureRouter(r *gin.RouterGroup) {
+	r.GET("/v1/connext/chains", func(c *gin.Context) {
+		chain, err := t.GetChain()
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		providers, err := t.GetChainProviders()
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		c.JSON(http.StatusOK, Chains{Network: t.network, Chain: chain, Providers: providers})
+	})
+
+	r.GET("/v1/connext/config", func(c *gin.Context) {
+		resp, err := t.GetConfig(c.Request.Context())
+		handleVectorResponse(c, resp, err)
+	})
+
+	r.GET("/v1/connext/channels", func(c *gin.Context) {
+		resp, err := t.GetChannels(c.Request.Context())
+		handleVectorResponse(c, resp, err)
+	})
+
+	r.GET("/v1/connext/channels/:address", func(c *gin.Context) {
+		resp, err := t.GetChannel(c.Request.Context(), c.Param("address"))
+		handleVectorResponse(c, resp, err)
+	})
+
+	r.GET("/v1/connext/channels/:address/balances", func(c *gin.Context) {
+		resp, err := t.GetBalances(c.Request.Context(), c.Param("address"))
+		handleVectorResponse(c, resp, err)
+	})
+
+	r.GET("/v1/connext/transfers", func(c *gin.Context) {
+		var params TransferFilter
+		if err := c.BindQuery(&params); err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := t.GetTransfers(c.Request.Context(), params)
+		handleVectorResponse(c, resp, err)
+	})
+
+	r.POST("/v1/connext/deposit", func(c *gin.Context) {
+		var params DepositParams
+		if err := c.BindJSON(&params); err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := t.ReconcileDeposit(c.Request.Context(), params.ChannelAddress, params.AssetId)
+		handleVectorResponse(c, resp, err)
+	})
+
+	r.POST("/v1/connext/withdraw", func(c *gin.Context) {
+		var params WithdrawParams
+		if err := c.BindJSON(&params); err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp, err := t.Withdraw(c.Request.Context(), params)
+		handleVectorResponse(c, resp, err)
+	})
+}
@@ -0,0 +1,47 @@
+package connext
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Chain is an Ethereum chain connext can be configured for
+type Chain struct {
+	Id   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+var (
+	// networkChains maps the networks of opendex-docker to the chain of their connext node
+	networkChains = map[string]Chain{
+		"mainnet": {Id: 1, Name: "Mainnet"},
+		"testnet": {Id: 4, Name: "Rinkeby"},
+		"simnet":  {Id: 1337, Name: "Local"},
+	}
+
+	knownChains = map[int64]string{
+		1:    "Mainnet",
+		3:    "Ropsten",
+		4:    "Rinkeby",
+		5:    "Goerli",
+		42:   "Kovan",
+		1337: "Local",
+	}
+)
+
+// ChainOf returns the chain of a network
+func ChainOf(network string) (Chain, error) {
+	chain, ok := networkChains[network]
+	if !ok {
+		return Chain{}, fmt.Errorf("no Ethereum chain for network %q", network)
+	}
+	return chain, nil
+}
+
+// ChainName returns the name of a chain id, or the id itself if the chain is unknown
+func ChainName(id int64) string {
+	if name, ok := knownChains[id]; ok {
+		return name
+	}
+	return strconv.FormatInt(id, 10)
+}
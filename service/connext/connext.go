@@ -4,15 +4,28 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	docker "github.com/docker/docker/client"
 	"github.com/opendexnetwork/opendex-docker-api/config"
 	"github.com/opendexnetwork/opendex-docker-api/service/core"
 	"github.com/opendexnetwork/opendex-docker-api/service/opendexd"
+	"sort"
+	"strconv"
 )
 
 type Service struct {
 	*core.SingleContainerService
 	*RpcClient
+
+	network string
+}
+
+// ChainProvider is an entry of chainProviders in VECTOR_CONFIG
+type ChainProvider struct {
+	Chain
+	Url string `json:"url"`
+	// Expected is set for the chain of the network
+	Expected bool `json:"expected"`
 }
 
 func New(
@@ -20,6 +33,7 @@ func New(
 	services map[string]core.Service,
 	containerName string,
 	dockerClient *docker.Client,
+	network string,
 	rpcConfig config.RpcConfig,
 ) *Service {
 	base := core.NewSingleContainerService(name, services, containerName, dockerClient)
@@ -27,6 +41,7 @@ func New(
 	return &Service{
 		SingleContainerService: base,
 		RpcClient:              NewRpcClient(rpcConfig, base),
+		network:                network,
 	}
 }
 
@@ -57,22 +72,68 @@ func (t *Service) GetStatus(ctx context.Context) string {
 	}
 }
 
-func (t *Service) GetEthProvider() (string, error) {
+// GetChain returns the chain of the network the connext node is expected to use
+func (t *Service) GetChain() (Chain, error) {
+	return ChainOf(t.network)
+}
+
+func (t *Service) getChainProviders() (map[string]string, error) {
 	value, err := t.Getenv("VECTOR_CONFIG")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if value == "" {
-		return "", errors.New("VECTOR_CONFIG not found")
+		return nil, errors.New("VECTOR_CONFIG not found")
 	}
 	var cfg struct {
 		ChainProviders map[string]string `json:"chainProviders"`
 	}
 	if err := json.Unmarshal([]byte(value), &cfg); err != nil {
+		return nil, fmt.Errorf("invalid VECTOR_CONFIG: %s", err)
+	}
+	return cfg.ChainProviders, nil
+}
+
+// GetChainProviders returns every chain provider of VECTOR_CONFIG sorted by chain id
+func (t *Service) GetChainProviders() ([]ChainProvider, error) {
+	providers, err := t.getChainProviders()
+	if err != nil {
+		return nil, err
+	}
+	expected, _ := t.GetChain()
+	result := []ChainProvider{}
+	for key, url := range providers {
+		id, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chain id in VECTOR_CONFIG: %s", key)
+		}
+		result = append(result, ChainProvider{
+			Chain:    Chain{Id: id, Name: ChainName(id)},
+			Url:      url,
+			Expected: id == expected.Id,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Id < result[j].Id
+	})
+	return result, nil
+}
+
+// GetEthProvider returns the provider of the chain of the network
+func (t *Service) GetEthProvider() (string, error) {
+	chain, err := t.GetChain()
+	if err != nil {
 		return "", err
 	}
-	// FIXME don't use "4" in mainnet, better remove this magic number
-	return cfg.ChainProviders["4"], nil
+	providers, err := t.getChainProviders()
+	if err != nil {
+		return "", err
+	}
+	provider, ok := providers[strconv.FormatInt(chain.Id, 10)]
+	if !ok || provider == "" {
+		return "", fmt.Errorf("VECTOR_CONFIG has no provider for chain %d (%s) of %s", chain.Id, chain.Name, t.network)
+	}
+	return provider, nil
 }
 
 func (t *Service) Close() error {
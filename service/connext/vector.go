@@ -0,0 +1,336 @@
+package connext
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	errNoNode = errors.New("the vector node has no public identifier")
+)
+
+// VectorError is a non-2xx response of the vector node
+type VectorError struct {
+	Status  int
+	Message string
+}
+
+func (t *VectorError) Error() string {
+	return t.Message
+}
+
+type NodeConfig struct {
+	Index            int                          `json:"index"`
+	PublicIdentifier string                       `json:"publicIdentifier"`
+	SignerAddress    string                       `json:"signerAddress"`
+	ChainAddresses   map[string]map[string]string `json:"chainAddresses"`
+}
+
+// Balance holds the amounts (in wei) of the participants in To
+type Balance struct {
+	Amount []string `json:"amount"`
+	To     []string `json:"to"`
+}
+
+type NetworkContext struct {
+	ChainId                 int64  `json:"chainId"`
+	ChannelFactoryAddress   string `json:"channelFactoryAddress"`
+	TransferRegistryAddress string `json:"transferRegistryAddress"`
+}
+
+type ChannelState struct {
+	ChannelAddress     string          `json:"channelAddress"`
+	Alice              string          `json:"alice"`
+	Bob                string          `json:"bob"`
+	AliceIdentifier    string          `json:"aliceIdentifier"`
+	BobIdentifier      string          `json:"bobIdentifier"`
+	AssetIds           []string        `json:"assetIds"`
+	Balances           []Balance       `json:"balances"`
+	ProcessedDepositsA []string        `json:"processedDepositsA"`
+	ProcessedDepositsB []string        `json:"processedDepositsB"`
+	DefundNonces       []string        `json:"defundNonces"`
+	Timeout            string          `json:"timeout"`
+	Nonce              int64           `json:"nonce"`
+	InDispute          bool            `json:"inDispute"`
+	NetworkContext     NetworkContext  `json:"networkContext"`
+	LatestUpdate       json.RawMessage `json:"latestUpdate,omitempty"`
+}
+
+// AssetBalance is the balance of an asset of a channel from the point of view of the node
+type AssetBalance struct {
+	AssetId           string `json:"assetId"`
+	Local             string `json:"local"`
+	Remote            string `json:"remote"`
+	ProcessedDeposits string `json:"processedDeposits"`
+}
+
+type Transfer struct {
+	TransferId          string          `json:"transferId"`
+	ChannelAddress      string          `json:"channelAddress"`
+	ChainId             int64           `json:"chainId"`
+	AssetId             string          `json:"assetId"`
+	Balance             Balance         `json:"balance"`
+	Initiator           string          `json:"initiator"`
+	Responder           string          `json:"responder"`
+	InitiatorIdentifier string          `json:"initiatorIdentifier"`
+	ResponderIdentifier string          `json:"responderIdentifier"`
+	TransferDefinition  string          `json:"transferDefinition"`
+	TransferTimeout     string          `json:"transferTimeout"`
+	TransferState       json.RawMessage `json:"transferState,omitempty"`
+	TransferResolver    json.RawMessage `json:"transferResolver,omitempty"`
+	ChannelNonce        int64           `json:"channelNonce"`
+	InDispute           bool            `json:"inDispute"`
+	Meta                json.RawMessage `json:"meta,omitempty"`
+}
+
+type TransferFilter struct {
+	ChannelAddress string `form:"channelAddress" json:"channelAddress"`
+	Active         bool   `form:"active" json:"active"`
+}
+
+type DepositResult struct {
+	ChannelAddress  string `json:"channelAddress"`
+	TransactionHash string `json:"transactionHash,omitempty"`
+}
+
+type WithdrawParams struct {
+	ChannelAddress string `json:"channelAddress" binding:"required"`
+	AssetId        string `json:"assetId" binding:"required"`
+	// Amount and Fee are in wei
+	Amount    string `json:"amount" binding:"required"`
+	Recipient string `json:"recipient" binding:"required"`
+	Fee       string `json:"fee"`
+}
+
+type WithdrawResult struct {
+	ChannelAddress  string `json:"channelAddress"`
+	TransferId      string `json:"transferId"`
+	TransactionHash string `json:"transactionHash,omitempty"`
+}
+
+func (t *RpcClient) request(ctx context.Context, method string, path string, body interface{}, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, t.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var e struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(data, &e); err != nil || e.Message == "" {
+			e.Message = strings.TrimSpace(string(data))
+		}
+		return &VectorError{Status: resp.StatusCode, Message: fmt.Sprintf("%s %s: %s", method, path, e.Message)}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+
+func (t *RpcClient) GetConfig(ctx context.Context) ([]NodeConfig, error) {
+	result := []NodeConfig{}
+	if err := t.request(ctx, http.MethodGet, "/config", nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// node returns the config of the first (the only one of opendex-docker) signer of the vector node
+func (t *RpcClient) node(ctx context.Context) (*NodeConfig, error) {
+	configs, err := t.GetConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 || configs[0].PublicIdentifier == "" {
+		return nil, errNoNode
+	}
+	return &configs[0], nil
+}
+
+func (t *RpcClient) GetChannelAddresses(ctx context.Context) ([]string, error) {
+	node, err := t.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := []string{}
+	if err := t.request(ctx, http.MethodGet, fmt.Sprintf("/%s/channels", node.PublicIdentifier), nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (t *RpcClient) GetChannel(ctx context.Context, address string) (*ChannelState, error) {
+	node, err := t.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result ChannelState
+	path := fmt.Sprintf("/%s/channels/%s", node.PublicIdentifier, url.PathEscape(address))
+	if err := t.request(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetChannels returns the state of every channel of the node
+func (t *RpcClient) GetChannels(ctx context.Context) ([]ChannelState, error) {
+	addresses, err := t.GetChannelAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := []ChannelState{}
+	for _, address := range addresses {
+		channel, err := t.GetChannel(ctx, address)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *channel)
+	}
+	return result, nil
+}
+
+func amountOf(balance Balance, address string) string {
+	for i, to := range balance.To {
+		if strings.EqualFold(to, address) && i < len(balance.Amount) {
+			return balance.Amount[i]
+		}
+	}
+	return "0"
+}
+
+// GetBalances returns the balance of every asset of a channel
+func (t *RpcClient) GetBalances(ctx context.Context, address string) ([]AssetBalance, error) {
+	node, err := t.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+	channel, err := t.GetChannel(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	remote := channel.Alice
+	processed := channel.ProcessedDepositsB
+	if strings.EqualFold(channel.Alice, node.SignerAddress) {
+		remote = channel.Bob
+		processed = channel.ProcessedDepositsA
+	}
+	result := []AssetBalance{}
+	for i, assetId := range channel.AssetIds {
+		b := AssetBalance{AssetId: assetId, Local: "0", Remote: "0", ProcessedDeposits: "0"}
+		if i < len(channel.Balances) {
+			b.Local = amountOf(channel.Balances[i], node.SignerAddress)
+			b.Remote = amountOf(channel.Balances[i], remote)
+		}
+		if i < len(processed) {
+			b.ProcessedDeposits = processed[i]
+		}
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+// GetTransfers returns the transfers of the node, optionally of a channel or only the active ones
+func (t *RpcClient) GetTransfers(ctx context.Context, filter TransferFilter) ([]Transfer, error) {
+	node, err := t.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var path string
+	if filter.Active {
+		if filter.ChannelAddress == "" {
+			return nil, &VectorError{Status: http.StatusBadRequest, Message: "active transfers require a channelAddress"}
+		}
+		path = fmt.Sprintf("/%s/channels/%s/active-transfers", node.PublicIdentifier, url.PathEscape(filter.ChannelAddress))
+	} else {
+		path = fmt.Sprintf("/%s/transfers", node.PublicIdentifier)
+		if filter.ChannelAddress != "" {
+			path += "?" + url.Values{"channelAddress": {filter.ChannelAddress}}.Encode()
+		}
+	}
+	result := []Transfer{}
+	if err := t.request(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReconcileDeposit credits the on-chain deposits of an asset to the channel
+func (t *RpcClient) ReconcileDeposit(ctx context.Context, channelAddress string, assetId string) (*DepositResult, error) {
+	node, err := t.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]string{
+		"publicIdentifier": node.PublicIdentifier,
+		"channelAddress":   channelAddress,
+		"assetId":          assetId,
+	}
+	var result DepositResult
+	if err := t.request(ctx, http.MethodPost, "/deposit", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Withdraw withdraws an amount of an asset from a channel to an on-chain address
+func (t *RpcClient) Withdraw(ctx context.Context, params WithdrawParams) (*WithdrawResult, error) {
+	for _, v := range []string{params.Amount, params.Fee} {
+		if v == "" {
+			continue
+		}
+		if n, ok := new(big.Int).SetString(v, 10); !ok || n.Sign() < 0 {
+			return nil, &VectorError{Status: http.StatusBadRequest, Message: fmt.Sprintf("invalid amount: %s", v)}
+		}
+	}
+	node, err := t.node(ctx)
+	if err != nil {
+		return nil, err
+	}
+	body := map[string]string{
+		"publicIdentifier": node.PublicIdentifier,
+		"channelAddress":   params.ChannelAddress,
+		"assetId":          params.AssetId,
+		"amount":           params.Amount,
+		"recipient":        params.Recipient,
+	}
+	if params.Fee != "" {
+		body["fee"] = params.Fee
+	}
+	var result WithdrawResult
+	if err := t.request(ctx, http.MethodPost, "/withdraw", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"fmt"
 	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
 	"github.com/sirupsen/logrus"
 )
 
@@ -34,6 +35,9 @@ func (t *AbstractService) GetName() string {
 func (t *AbstractService) ConfigureRouter(r *gin.RouterGroup) {
 }
 
+func (t *AbstractService) ConfigureSpec(spec *openapi.Spec) {
+}
+
 func (t *AbstractService) Close() {
 }
 
@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
 	"io"
 )
 
@@ -15,6 +16,7 @@ type Service interface {
 	DockerEventListener
 
 	ConfigureRouter(r *gin.RouterGroup)
+	ConfigureSpec(spec *openapi.Spec)
 
 	GetName() string
 	GetStatus(ctx context.Context) string
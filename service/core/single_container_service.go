@@ -177,28 +177,54 @@ func (t *SingleContainerService) FollowLogs(since string, tail string) (<-chan s
 func (t *SingleContainerService) FollowLogs2() (<-chan string, func(), error) {
 	ch := make(chan string)
 	var running = true
+	var stopCurrent func()
+	var mutex sync.Mutex
 
 	go func() {
-		for running {
+		for {
 			c := t.WaitContainerRunning()
 			startedAt := c.State.StartedAt
 			lines, stop, err := t.FollowLogs(startedAt, "")
 			if err != nil {
-				t.logger.Error("Failed to follow logs: %s", err)
+				t.logger.Errorf("Failed to follow logs: %s", err)
 				time.Sleep(3 * time.Second)
+				continue
 			}
+
+			mutex.Lock()
+			if !running {
+				mutex.Unlock()
+				stop()
+				break
+			}
+			stopCurrent = stop
+			mutex.Unlock()
+
 			for line := range lines {
-				if !running {
-					break
-				}
 				ch <- line
 			}
 			stop()
+
+			mutex.Lock()
+			stopCurrent = nil
+			stopped := !running
+			mutex.Unlock()
+			if stopped {
+				break
+			}
 		}
 		close(ch)
 	}()
 
-	return ch, func() { running = false }, nil
+	return ch, func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		running = false
+		// closing the Docker log stream ends the current lines
+		if stopCurrent != nil {
+			stopCurrent()
+		}
+	}, nil
 }
 
 func (t *SingleContainerService) Exec1(command []string) (string, error) {
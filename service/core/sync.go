@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// SyncSmoothing is the weight of the latest rate in the moving average of the sync rate
+	SyncSmoothing = 0.2
+
+	// ErrNotSyncing is returned by GetSyncHeights when the service doesn't sync a chain itself (e.g. in light mode)
+	ErrNotSyncing = errors.New("not syncing a chain")
+)
+
+// Syncer is implemented by the services which sync a chain
+type Syncer interface {
+	// GetSyncHeights returns the height the service is at and the height it syncs to
+	GetSyncHeights(ctx context.Context) (current int64, total int64, err error)
+}
+
+type SyncProgress struct {
+	Current  int64   `json:"current"`
+	Total    int64   `json:"total"`
+	Progress float64 `json:"progress"`
+	Synced   bool    `json:"synced"`
+	// Rate is the smoothed sync rate in blocks per second
+	Rate float64 `json:"rate"`
+	// Eta is the estimated number of seconds to sync, -1 when unknown
+	Eta       int64     `json:"eta"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SyncTracker computes the sync rate and ETA of a service from samples of its heights
+type SyncTracker struct {
+	mutex   *sync.RWMutex
+	current int64
+	total   int64
+	rate    float64
+	// hasRate is false until two samples are known, so that a stalled sync (a zero rate) is still smoothed
+	hasRate bool
+	sampled time.Time
+}
+
+func NewSyncTracker() *SyncTracker {
+	return &SyncTracker{
+		mutex: &sync.RWMutex{},
+	}
+}
+
+// Sample records the heights of the service at a time
+func (t *SyncTracker) Sample(current int64, total int64, at time.Time) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if !t.sampled.IsZero() {
+		elapsed := at.Sub(t.sampled).Seconds()
+		if elapsed <= 0 {
+			return
+		}
+		if current < t.current {
+			// the service restarted or reorged; the old rate says nothing about the new sync
+			t.rate = 0
+			t.hasRate = false
+		} else {
+			rate := float64(current-t.current) / elapsed
+			if t.hasRate {
+				t.rate = SyncSmoothing*rate + (1-SyncSmoothing)*t.rate
+			} else {
+				t.rate = rate
+				t.hasRate = true
+			}
+		}
+	}
+	t.current = current
+	t.total = total
+	t.sampled = at
+}
+
+// Reset forgets the samples, e.g. when the service stopped syncing a chain
+func (t *SyncTracker) Reset() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.current = 0
+	t.total = 0
+	t.rate = 0
+	t.hasRate = false
+	t.sampled = time.Time{}
+}
+
+// Progress returns the latest sample with the rate and ETA or nil if there is no sample yet
+func (t *SyncTracker) Progress() *SyncProgress {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.sampled.IsZero() {
+		return nil
+	}
+	total := t.total
+	if total < t.current {
+		total = t.current
+	}
+	result := &SyncProgress{
+		Current:   t.current,
+		Total:     total,
+		Synced:    total > 0 && t.current == total,
+		Rate:      t.rate,
+		Eta:       -1,
+		UpdatedAt: t.sampled,
+	}
+	if total > 0 {
+		result.Progress = float64(t.current) / float64(total) * 100
+	}
+	if result.Synced {
+		result.Eta = 0
+	} else if t.rate > 0 {
+		result.Eta = int64(float64(total-t.current) / t.rate)
+	}
+	return result
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSyncTracker(t *testing.T) {
+	type sample struct {
+		current int64
+		total   int64
+		// seconds since the start, a negative value resets the tracker
+		at int
+	}
+	cases := []struct {
+		name     string
+		samples  []sample
+		expected *SyncProgress
+	}{
+		{
+			name:     "no sample",
+			expected: nil,
+		},
+		{
+			name:     "first sample",
+			samples:  []sample{{100, 1000, 0}},
+			expected: &SyncProgress{Current: 100, Total: 1000, Progress: 10, Rate: 0, Eta: -1},
+		},
+		{
+			name:     "steady",
+			samples:  []sample{{100, 1000, 0}, {200, 1000, 10}},
+			expected: &SyncProgress{Current: 200, Total: 1000, Progress: 20, Rate: 10, Eta: 80},
+		},
+		{
+			// a stalled sync lowers the rate gradually instead of being taken as the first rate
+			name:     "stall",
+			samples:  []sample{{100, 1000, 0}, {200, 1000, 10}, {200, 1000, 20}},
+			expected: &SyncProgress{Current: 200, Total: 1000, Progress: 20, Rate: 8, Eta: 100},
+		},
+		{
+			name:     "stall from the start",
+			samples:  []sample{{100, 1000, 0}, {100, 1000, 10}, {200, 1000, 20}},
+			expected: &SyncProgress{Current: 200, Total: 1000, Progress: 20, Rate: 2, Eta: 400},
+		},
+		{
+			name:     "restart",
+			samples:  []sample{{100, 1000, 0}, {200, 1000, 10}, {50, 1000, 20}},
+			expected: &SyncProgress{Current: 50, Total: 1000, Progress: 5, Rate: 0, Eta: -1},
+		},
+		{
+			// the rate after a restart isn't smoothed with the rate before it
+			name:     "rate after a restart",
+			samples:  []sample{{100, 1000, 0}, {200, 1000, 10}, {50, 1000, 20}, {70, 1000, 30}},
+			expected: &SyncProgress{Current: 70, Total: 1000, Progress: 7, Rate: 2, Eta: 465},
+		},
+		{
+			name:     "same time",
+			samples:  []sample{{100, 1000, 0}, {200, 1000, 10}, {300, 1000, 10}},
+			expected: &SyncProgress{Current: 200, Total: 1000, Progress: 20, Rate: 10, Eta: 80},
+		},
+		{
+			name:     "total below current",
+			samples:  []sample{{100, 1000, 0}, {200, 150, 10}},
+			expected: &SyncProgress{Current: 200, Total: 200, Progress: 100, Synced: true, Rate: 10, Eta: 0},
+		},
+		{
+			name:     "reset",
+			samples:  []sample{{100, 1000, 0}, {200, 1000, 10}, {0, 0, -1}},
+			expected: nil,
+		},
+		{
+			name:     "rate after a reset",
+			samples:  []sample{{100, 1000, 0}, {200, 1000, 10}, {0, 0, -1}, {500, 1000, 20}, {510, 1000, 30}},
+			expected: &SyncProgress{Current: 510, Total: 1000, Progress: 51, Rate: 1, Eta: 490},
+		},
+	}
+
+	start := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	for _, c := range cases {
+		tracker := NewSyncTracker()
+		var last time.Time
+		for _, s := range c.samples {
+			if s.at < 0 {
+				tracker.Reset()
+				continue
+			}
+			last = start.Add(time.Duration(s.at) * time.Second)
+			tracker.Sample(s.current, s.total, last)
+		}
+		p := tracker.Progress()
+		if c.expected == nil {
+			if p != nil {
+				t.Errorf("%s: expected no progress, got %+v", c.name, p)
+			}
+			continue
+		}
+		if p == nil {
+			t.Errorf("%s: no progress", c.name)
+			continue
+		}
+		c.expected.UpdatedAt = last
+		// the rates and percentages are floats
+		if math.Abs(p.Rate-c.expected.Rate) < 1e-9 {
+			p.Rate = c.expected.Rate
+		}
+		if math.Abs(p.Progress-c.expected.Progress) < 1e-9 {
+			p.Progress = c.expected.Progress
+		}
+		if *p != *c.expected {
+			t.Errorf("%s: got %+v, expected %+v", c.name, *p, *c.expected)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package geth
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/opendexnetwork/opendex-docker-api/launcher"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	"github.com/opendexnetwork/opendex-docker-api/utils"
+	"net/http"
+)
+
+type Providers struct {
+	// Current is the provider of connext, Recommended the best healthy light provider
+	Current     string           `json:"current"`
+	Mode        Mode             `json:"mode"`
+	Recommended string           `json:"recommended"`
+	Failover    bool             `json:"failover"`
+	Providers   []ProviderHealth `json:"providers"`
+}
+
+type BalancesParams struct {
+	// Address defaults to the signer of connext
+	Address string `form:"address" json:"address"`
+}
+
+type SwitchProviderParams struct {
+	// Provider defaults to the recommended one
+	Provider string `json:"provider"`
+}
+
+func (t *Service) ConfigureSpec(spec *openapi.Spec) {
+	t.client.ConfigureSpec(spec, t.GetName())
+	spec.Route(http.MethodGet, "/api/v1/geth/providers", "Get the health of the light providers").
+		Tag("geth").
+		Describe("Returns the latency, chain id, block lag and error rate of every light provider, probed in the background, the best first.").
+		Returns(Providers{})
+	spec.Route(http.MethodPost, "/api/v1/geth/providers/switch", "Switch connext to another light provider").
+		Tag("geth").
+		Describe("Asks the attached launcher to recreate connext with the provider (the recommended one by default). Returns 409 while another switch, manual or by the failover, is in progress.").
+		Body(SwitchProviderParams{}).Returns(Providers{})
+	spec.Route(http.MethodGet, "/api/v1/geth/syncing", "Get the sync state of geth").
+		Tag("geth").
+		Describe("Returns eth_syncing (starting, current and highest block, known and pulled states), net_peerCount and web3_clientVersion of the provider of the current mode.").
+		Returns(SyncState{})
+	spec.Route(http.MethodGet, "/api/v1/geth/balances", "Get the ether and token balances of the connext signer").
+		Tag("geth").
+		Describe("Queries eth_getBalance and the ERC20 balanceOf of the connext currencies of opendexd and of --eth-token through the provider of the current mode.").
+		QueryParams(BalancesParams{}).Returns(Balances{})
+}
+
+func (t *Service) providers() Providers {
+	result := Providers{
+		Failover:  t.failover,
+		Providers: []ProviderHealth{},
+	}
+	result.Current, _ = t.getProvider()
+	result.Mode, _ = t.getMode()
+	if t.prober != nil {
+		result.Providers = t.prober.Providers()
+		result.Recommended = t.prober.Recommended()
+	}
+	return result
+}
+
+func (t *Service) ConfigureRouter(r *gin.RouterGroup) {
+	t.client.ConfigureRouter(r, t.GetName())
+
+	r.GET("/v1/geth/providers", func(c *gin.Context) {
+		c.JSON(http.StatusOK, t.providers())
+	})
+
+	r.GET("/v1/geth/syncing", func(c *gin.Context) {
+		resp, err := t.GetSyncState(c.Request.Context())
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+
+	r.GET("/v1/geth/balances", func(c *gin.Context) {
+		var params BalancesParams
+		if err := c.BindQuery(&params); err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if params.Address != "" && !addressPattern.MatchString(params.Address) {
+			utils.JsonError(c, fmt.Sprintf("invalid address: %s", params.Address), http.StatusBadRequest)
+			return
+		}
+		resp, err := t.GetBalances(c.Request.Context(), params.Address)
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+
+	r.POST("/v1/geth/providers/switch", func(c *gin.Context) {
+		var params SwitchProviderParams
+		if err := c.BindJSON(&params); err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		provider := params.Provider
+		if provider == "" {
+			if t.prober != nil {
+				provider = t.prober.Recommended()
+			}
+			if provider == "" {
+				utils.JsonError(c, "no healthy light provider", http.StatusConflict)
+				return
+			}
+		}
+		if !t.isLightProvider(provider) {
+			utils.JsonError(c, fmt.Sprintf("not a light provider: %s", provider), http.StatusBadRequest)
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), launcher.RequestTimeout)
+		defer cancel()
+		if err := t.SwitchProvider(ctx, provider); err != nil {
+			if err == ErrSwitching {
+				utils.JsonError(c, err.Error(), http.StatusConflict)
+			} else {
+				utils.JsonError(c, err.Error(), http.StatusServiceUnavailable)
+			}
+			return
+		}
+		c.JSON(http.StatusOK, t.providers())
+	})
+}
@@ -0,0 +1,215 @@
+package geth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker-api/rpc"
+	"github.com/opendexnetwork/opendex-docker-api/service/opendexd"
+	pb "github.com/opendexnetwork/opendex-docker-api/service/opendexd/opendexrpc"
+	"math/big"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// the ERC20 function selectors
+	selectorBalanceOf = "0x70a08231"
+	selectorDecimals  = "0x313ce567"
+
+	etherDecimals = 18
+)
+
+var (
+	addressPattern = regexp.MustCompile("^0x[0-9a-fA-F]{40}$")
+
+	errNoSigner = errors.New("the connext signer address is unknown")
+)
+
+type TokenBalance struct {
+	Symbol string `json:"symbol"`
+	// Contract is empty for ether
+	Contract string `json:"contract,omitempty"`
+	Decimals int    `json:"decimals"`
+	// Balance is in the smallest unit (wei for ether), Amount in whole tokens
+	Balance string `json:"balance"`
+	Amount  string `json:"amount"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Balances struct {
+	Address  string         `json:"address"`
+	Mode     Mode           `json:"mode"`
+	Provider string         `json:"provider"`
+	Ether    TokenBalance   `json:"ether"`
+	Tokens   []TokenBalance `json:"tokens"`
+}
+
+// parseQuantity parses a hex quantity or a 32 bytes word of an eth_call result
+func parseQuantity(value string) (*big.Int, error) {
+	digits := strings.TrimPrefix(value, "0x")
+	if digits == "" {
+		return nil, fmt.Errorf("empty value")
+	}
+	n, ok := new(big.Int).SetString(digits, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid hex value: %s", value)
+	}
+	return n, nil
+}
+
+// formatUnits formats an amount of the smallest unit in whole units, e.g. 1500000000000000000 wei as 1.5
+func formatUnits(value *big.Int, decimals int) string {
+	s := new(big.Int).Abs(value).String()
+	if decimals > 0 {
+		if len(s) <= decimals {
+			s = strings.Repeat("0", decimals-len(s)+1) + s
+		}
+		s = strings.TrimRight(s[:len(s)-decimals]+"."+s[len(s)-decimals:], "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	if value.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// ethClient returns the client of the provider of the current mode
+func (t *Service) ethClient() (*rpc.JsonRpcClient, Mode, string, error) {
+	mode, err := t.getMode()
+	if err != nil {
+		return nil, mode, "", err
+	}
+	if mode == Native {
+		return t.client, mode, t.client.Url(), nil
+	}
+	provider, err := t.getProvider()
+	if err != nil {
+		return nil, mode, "", err
+	}
+	return rpc.NewJsonRpcClient(provider, &http.Client{Timeout: ProbeTimeout}, nil, nil), mode, provider, nil
+}
+
+func callString(ctx context.Context, client *rpc.JsonRpcClient, method string, params ...interface{}) (string, error) {
+	var result string
+	if err := client.CallFor(ctx, &result, method, params...); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// SignerAddress returns the Ethereum address of the connext node
+func (t *Service) SignerAddress(ctx context.Context) (string, error) {
+	connextSvc, err := t.getL2Service()
+	if err != nil {
+		return "", err
+	}
+	configs, err := connextSvc.GetConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(configs) == 0 || configs[0].SignerAddress == "" {
+		return "", errNoSigner
+	}
+	return configs[0].SignerAddress, nil
+}
+
+// tokenContracts returns the ERC20 contracts of the connext currencies of opendexd and of --eth-token by symbol
+func (t *Service) tokenContracts(ctx context.Context) map[string]string {
+	result := map[string]string{}
+	if svc, ok := t.GetService("opendexd").(*opendexd.Service); ok {
+		resp, err := svc.ListCurrencies(ctx)
+		if err != nil {
+			t.GetLogger().Debugf("Failed to list the currencies of opendexd: %s", err)
+		} else {
+			for _, c := range resp.Currencies {
+				// ether itself has the zero address
+				if c.SwapClient != pb.Currency_CONNEXT || strings.Trim(strings.TrimPrefix(c.TokenAddress, "0x"), "0") == "" {
+					continue
+				}
+				result[c.Currency] = c.TokenAddress
+			}
+		}
+	}
+	for symbol, contract := range t.tokens {
+		result[symbol] = contract
+	}
+	return result
+}
+
+func tokenBalance(ctx context.Context, client *rpc.JsonRpcClient, symbol string, contract string, address string) TokenBalance {
+	b := TokenBalance{Symbol: symbol, Contract: contract}
+	if !addressPattern.MatchString(contract) {
+		b.Error = fmt.Sprintf("invalid contract address: %s", contract)
+		return b
+	}
+	call := func(data string) (*big.Int, error) {
+		result, err := callString(ctx, client, "eth_call", map[string]string{"to": contract, "data": data}, "latest")
+		if err != nil {
+			return nil, err
+		}
+		return parseQuantity(result)
+	}
+	decimals, err := call(selectorDecimals)
+	if err != nil {
+		b.Error = fmt.Sprintf("decimals(): %s", err)
+		return b
+	}
+	b.Decimals = int(decimals.Int64())
+	balance, err := call(selectorBalanceOf + strings.Repeat("0", 24) + strings.ToLower(strings.TrimPrefix(address, "0x")))
+	if err != nil {
+		b.Error = fmt.Sprintf("balanceOf(): %s", err)
+		return b
+	}
+	b.Balance = balance.String()
+	b.Amount = formatUnits(balance, b.Decimals)
+	return b
+}
+
+// GetBalances returns the ether and token balances of an address, the connext signer by default
+func (t *Service) GetBalances(ctx context.Context, address string) (*Balances, error) {
+	if address == "" {
+		var err error
+		if address, err = t.SignerAddress(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if !addressPattern.MatchString(address) {
+		return nil, fmt.Errorf("invalid address: %s", address)
+	}
+	client, mode, provider, err := t.ethClient()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Balances{
+		Address:  address,
+		Mode:     mode,
+		Provider: provider,
+		Ether:    TokenBalance{Symbol: "ETH", Decimals: etherDecimals},
+		Tokens:   []TokenBalance{},
+	}
+	s, err := callString(ctx, client, "eth_getBalance", address, "latest")
+	if err != nil {
+		return nil, err
+	}
+	balance, err := parseQuantity(s)
+	if err != nil {
+		return nil, err
+	}
+	result.Ether.Balance = balance.String()
+	result.Ether.Amount = formatUnits(balance, etherDecimals)
+
+	contracts := t.tokenContracts(ctx)
+	var symbols []string
+	for symbol := range contracts {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		result.Tokens = append(result.Tokens, tokenBalance(ctx, client, symbol, contracts[symbol], address))
+	}
+	return result, nil
+}
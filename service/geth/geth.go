@@ -5,11 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/launcher"
 	"github.com/opendexnetwork/opendex-docker-api/service/connext"
 	"github.com/opendexnetwork/opendex-docker-api/service/core"
 	docker "github.com/docker/docker/client"
 	"github.com/ybbus/jsonrpc"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 type Service struct {
@@ -18,6 +21,18 @@ type Service struct {
 
 	l2ServiceName  string
 	lightProviders []string
+	// chainId is the Ethereum chain of the network
+	chainId int64
+
+	// tokens are the ERC20 contracts of --eth-token by symbol
+	tokens map[string]string
+
+	prober *Prober
+	// failover switches connext to the recommended provider when the current light provider fails
+	failover        bool
+	unhealthyRounds int
+	switching       bool
+	mutex           *sync.Mutex
 }
 
 type Mode string
@@ -37,28 +52,67 @@ func New(
 	dockerClient *docker.Client,
 	l2ServiceName string,
 	lightProviders []string,
+	chainId int64,
+	failover bool,
+	tokens map[string]string,
 	rpcConfig config.RpcConfig,
 ) *Service {
-	return &Service{
-		SingleContainerService: core.NewSingleContainerService(name, services, containerName, dockerClient),
+	base := core.NewSingleContainerService(name, services, containerName, dockerClient)
+	s := &Service{
+		SingleContainerService: base,
 		RpcClient:              NewRpcClient(rpcConfig),
 		l2ServiceName:          l2ServiceName,
 		lightProviders:         lightProviders,
+		chainId:                chainId,
+		failover:               failover,
+		tokens:                 tokens,
+		mutex:                  &sync.Mutex{},
 	}
+	if len(lightProviders) > 0 {
+		s.prober = NewProber(lightProviders, chainId, base.GetLogger())
+		go s.prober.Start(s.checkFailover)
+	}
+	return s
+}
+
+// chainMismatchError is returned by checkEthRpc for a provider of another chain
+type chainMismatchError struct {
+	chainId  int64
+	expected int64
+}
+
+func (t *chainMismatchError) Error() string {
+	return fmt.Sprintf("provider is on chain %s instead of %s", connext.ChainName(t.chainId), connext.ChainName(t.expected))
 }
 
-func (t *Service) checkEthRpc(url string) bool {
+func (t *Service) checkEthRpc(url string) error {
 	client := jsonrpc.NewClientWithOpts(url, &jsonrpc.RPCClientOpts{})
 	result, err := client.Call("net_version")
 	if err != nil {
-		return false
+		return err
 	}
 	version, err := result.GetString()
 	if err != nil {
-		return false
+		return err
 	}
 	t.GetLogger().Infof("Ethereum provider %s net_version is %s", url, explainNetVersion(version))
-	return true
+	// the network id is the chain id on all chains connext uses
+	if id, err := strconv.ParseInt(version, 10, 64); err == nil && t.chainId != 0 && id != t.chainId {
+		return &chainMismatchError{chainId: id, expected: t.chainId}
+	}
+	return nil
+}
+
+// ethRpcStatus is the status of a provider checked by checkEthRpc
+func (t *Service) ethRpcStatus(provider string, ready string, unavailable string) string {
+	err := t.checkEthRpc(provider)
+	if err == nil {
+		return ready
+	}
+	if _, ok := err.(*chainMismatchError); ok {
+		return fmt.Sprintf("Error: %s", err)
+	}
+	return unavailable
 }
 
 func (t *Service) getL2Service() (*connext.Service, error) {
@@ -113,37 +167,33 @@ func (t *Service) getMode() (Mode, error) {
 func (t *Service) getExternalStatus() string {
 	provider, err := t.getProvider()
 	if err != nil {
-		return "No provider"
-	}
-	if t.checkEthRpc(provider) {
-		return "Ready (connected to external)"
-	} else {
-		return "Unavailable (connection to external failed)"
+		return fmt.Sprintf("No provider (%s)", err)
 	}
+	return t.ethRpcStatus(provider, "Ready (connected to external)", "Unavailable (connection to external failed)")
 }
 
 func (t *Service) getInfuraStatus() string {
 	provider, err := t.getProvider()
 	if err != nil {
-		return "No provider"
-	}
-	if t.checkEthRpc(provider) {
-		return "Ready (connected to Infura)"
-	} else {
-		return "Unavailable (connection to Infura failed)"
+		return fmt.Sprintf("No provider (%s)", err)
 	}
+	return t.ethRpcStatus(provider, "Ready (connected to Infura)", "Unavailable (connection to Infura failed)")
 }
 
 func (t *Service) getLightStatus() string {
 	provider, err := t.getProvider()
 	if err != nil {
-		return "No provider"
+		return fmt.Sprintf("No provider (%s)", err)
 	}
-	if t.checkEthRpc(provider) {
-		return "Ready (light mode)"
-	} else {
-		return "Unavailable (light mode failed)"
+	if t.prober != nil {
+		if h, ok := t.prober.Health(provider); ok {
+			if h.Healthy {
+				return "Ready (light mode)"
+			}
+			return fmt.Sprintf("Unavailable (light mode failed: %s)", h.Error)
+		}
 	}
+	return t.ethRpcStatus(provider, "Ready (light mode)", "Unavailable (light mode failed)")
 }
 
 func (t *Service) GetStatus(ctx context.Context) string {
@@ -170,7 +220,7 @@ func (t *Service) GetStatus(ctx context.Context) string {
 
 	// container is running
 
-	syncing, err := t.EthSyncing()
+	syncing, err := t.EthSyncing(ctx)
 	if err != nil {
 		return "Waiting for geth to come up..."
 	}
@@ -180,7 +230,7 @@ func (t *Service) GetStatus(ctx context.Context) string {
 		p := float32(current) / float32(total) * 100.0
 		return fmt.Sprintf("Syncing %.2f%% (%d/%d)", p, current, total)
 	} else {
-		blockNumber, err := t.EthBlockNumber()
+		blockNumber, err := t.EthBlockNumber(ctx)
 		if err != nil {
 			return "Waiting for geth to come up..."
 		}
@@ -192,7 +242,100 @@ func (t *Service) GetStatus(ctx context.Context) string {
 	}
 }
 
+// GetSyncHeights returns the current and highest block of the geth container. The providers of the other modes are
+// synced by others.
+func (t *Service) GetSyncHeights(ctx context.Context) (int64, int64, error) {
+	mode, err := t.getMode()
+	if err != nil {
+		return 0, 0, err
+	}
+	if mode != Native {
+		return 0, 0, core.ErrNotSyncing
+	}
+	syncing, err := t.EthSyncing(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	if syncing != nil {
+		return syncing.CurrentBlock, syncing.HighestBlock, nil
+	}
+	blockNumber, err := t.EthBlockNumber(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return blockNumber, blockNumber, nil
+}
+
+// checkFailover is called after every probe round. It asks the launcher to switch connext to the recommended
+// provider when the current one was unhealthy FailoverProbes times in a row.
+func (t *Service) checkFailover() {
+	if !t.failover || t.IsDisabled() {
+		return
+	}
+	mode, err := t.getMode()
+	if err != nil || mode != Light {
+		return
+	}
+	provider, err := t.getProvider()
+	if err != nil {
+		return
+	}
+	h, ok := t.prober.Health(provider)
+	if !ok {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if h.Healthy {
+		t.unhealthyRounds = 0
+		return
+	}
+	t.unhealthyRounds++
+	recommended := t.prober.Recommended()
+	if t.unhealthyRounds < FailoverProbes || t.switching || recommended == "" || recommended == provider {
+		return
+	}
+	go func() {
+		t.GetLogger().Infof("Switching the Ethereum provider from %s (%s) to %s", provider, h.Error, recommended)
+		ctx, cancel := context.WithTimeout(context.Background(), launcher.RequestTimeout)
+		defer cancel()
+		if err := t.SwitchProvider(ctx, recommended); err != nil {
+			t.GetLogger().Errorf("Failed to switch the Ethereum provider: %s", err)
+		}
+	}()
+}
+
+// SwitchProvider asks the attached launcher to recreate connext with one of the light providers. It fails with
+// ErrSwitching while another switch (manual or by the failover) is in progress and stops waiting for the launcher
+// when ctx is done.
+func (t *Service) SwitchProvider(ctx context.Context, provider string) error {
+	if !t.isLightProvider(provider) {
+		return fmt.Errorf("not a light provider: %s", provider)
+	}
+
+	t.mutex.Lock()
+	if t.switching {
+		t.mutex.Unlock()
+		return ErrSwitching
+	}
+	t.switching = true
+	t.mutex.Unlock()
+
+	defer func() {
+		t.mutex.Lock()
+		t.switching = false
+		t.unhealthyRounds = 0
+		t.mutex.Unlock()
+	}()
+
+	return launcher.SetEthProvider(ctx, provider)
+}
+
 func (t *Service) Close() error {
+	if t.prober != nil {
+		t.prober.Close()
+	}
 	_ = t.RpcClient.Close()
 	return nil
 }
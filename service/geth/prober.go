@@ -0,0 +1,251 @@
+package geth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker-api/rpc"
+	"github.com/opendexnetwork/opendex-docker-api/service/connext"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ProbeInterval = 30 * time.Second
+	ProbeTimeout  = 5 * time.Second
+
+	// ProbeWindow is the number of probes the error rate is computed from
+	ProbeWindow = 20
+	// MaxBlockLag is how many blocks a provider may be behind the highest one to be healthy
+	MaxBlockLag int64 = 5
+	// FailoverProbes is how many probes in a row the current provider must be unhealthy before it is replaced
+	FailoverProbes = 3
+
+	// ErrSwitching is returned by SwitchProvider while the launcher is recreating connext
+	ErrSwitching = errors.New("already switching the Ethereum provider")
+)
+
+type ProviderHealth struct {
+	Url       string    `json:"url"`
+	Healthy   bool      `json:"healthy"`
+	ChainId   int64     `json:"chainId"`
+	Block     int64     `json:"block"`
+	BlockLag  int64     `json:"blockLag"`
+	Latency   int64     `json:"latency"`
+	ErrorRate float64   `json:"errorRate"`
+	Error     string    `json:"error,omitempty"`
+	LastProbe time.Time `json:"lastProbe"`
+	Rank      int       `json:"rank"`
+}
+
+type providerState struct {
+	health ProviderHealth
+	// failures of the last ProbeWindow probes
+	failures []bool
+}
+
+// Prober measures the latency, chain id and block height of the light providers in the background and ranks them
+type Prober struct {
+	providers []string
+	// chainId is the chain the providers must be on
+	chainId int64
+	client  *http.Client
+	states  map[string]*providerState
+	mutex   *sync.RWMutex
+	stop    chan struct{}
+	logger  *logrus.Entry
+}
+
+func NewProber(providers []string, chainId int64, logger *logrus.Entry) *Prober {
+	states := map[string]*providerState{}
+	for _, p := range providers {
+		states[p] = &providerState{health: ProviderHealth{Url: p}}
+	}
+	return &Prober{
+		providers: providers,
+		chainId:   chainId,
+		client:    &http.Client{Timeout: ProbeTimeout},
+		states:    states,
+		mutex:     &sync.RWMutex{},
+		stop:      make(chan struct{}),
+		logger:    logger,
+	}
+}
+
+// Start probes the providers every ProbeInterval until Close. The callback is called after every round.
+func (t *Prober) Start(callback func()) {
+	ticker := time.NewTicker(ProbeInterval)
+	defer ticker.Stop()
+	for {
+		t.probeAll()
+		if callback != nil {
+			callback()
+		}
+		select {
+		case <-ticker.C:
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+func (t *Prober) Close() {
+	close(t.stop)
+}
+
+func (t *Prober) probe(ctx context.Context, url string) (chainId int64, block int64, latency int64, err error) {
+	client := rpc.NewJsonRpcClient(url, t.client, nil, nil)
+	start := time.Now()
+	resp, err := client.Call(ctx, "eth_chainId")
+	latency = time.Since(start).Milliseconds()
+	if err != nil {
+		return
+	}
+	if resp.Error != nil {
+		err = fmt.Errorf("eth_chainId: %s", resp.Error.Message)
+		return
+	}
+	s, err := resp.GetString()
+	if err != nil {
+		return
+	}
+	if chainId, err = parseHex(s); err != nil {
+		return
+	}
+
+	resp, err = client.Call(ctx, "eth_blockNumber")
+	if err != nil {
+		return
+	}
+	if resp.Error != nil {
+		err = fmt.Errorf("eth_blockNumber: %s", resp.Error.Message)
+		return
+	}
+	if s, err = resp.GetString(); err != nil {
+		return
+	}
+	block, err = parseHex(s)
+	return
+}
+
+func (t *Prober) probeAll() {
+	ctx, cancel := context.WithTimeout(context.Background(), ProbeTimeout)
+	defer cancel()
+
+	type result struct {
+		chainId, block, latency int64
+		err                     error
+	}
+	results := make([]result, len(t.providers))
+	var wg sync.WaitGroup
+	for i, p := range t.providers {
+		wg.Add(1)
+		go func(i int, p string) {
+			defer wg.Done()
+			r := &results[i]
+			r.chainId, r.block, r.latency, r.err = t.probe(ctx, p)
+		}(i, p)
+	}
+	wg.Wait()
+
+	var highest int64
+	for _, r := range results {
+		if r.err == nil && r.chainId == t.chainId && r.block > highest {
+			highest = r.block
+		}
+	}
+
+	now := time.Now()
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i, p := range t.providers {
+		r := results[i]
+		s := t.states[p]
+		s.failures = append(s.failures, r.err != nil)
+		if len(s.failures) > ProbeWindow {
+			s.failures = s.failures[len(s.failures)-ProbeWindow:]
+		}
+		failed := 0
+		for _, f := range s.failures {
+			if f {
+				failed++
+			}
+		}
+
+		h := &s.health
+		h.LastProbe = now
+		h.Latency = r.latency
+		h.ErrorRate = float64(failed) / float64(len(s.failures))
+		h.Error = ""
+		if r.err != nil {
+			h.Healthy = false
+			h.Error = r.err.Error()
+			t.logger.Debugf("Ethereum provider %s failed: %s", p, r.err)
+			continue
+		}
+		h.ChainId = r.chainId
+		h.Block = r.block
+		h.BlockLag = highest - r.block
+		switch {
+		case r.chainId != t.chainId:
+			h.Healthy = false
+			h.Error = fmt.Sprintf("on chain %s instead of %s", connext.ChainName(r.chainId), connext.ChainName(t.chainId))
+		case h.BlockLag > MaxBlockLag:
+			h.Healthy = false
+			h.Error = fmt.Sprintf("%d blocks behind", h.BlockLag)
+		default:
+			h.Healthy = true
+		}
+	}
+}
+
+// Providers returns the health of the providers, the best first
+func (t *Prober) Providers() []ProviderHealth {
+	t.mutex.RLock()
+	result := make([]ProviderHealth, 0, len(t.providers))
+	for _, p := range t.providers {
+		result = append(result, t.states[p].health)
+	}
+	t.mutex.RUnlock()
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Healthy != b.Healthy {
+			return a.Healthy
+		}
+		if a.ErrorRate != b.ErrorRate {
+			return a.ErrorRate < b.ErrorRate
+		}
+		if a.BlockLag != b.BlockLag {
+			return a.BlockLag < b.BlockLag
+		}
+		return a.Latency < b.Latency
+	})
+	for i := range result {
+		result[i].Rank = i + 1
+	}
+	return result
+}
+
+// Health returns the health of a provider or false if it hasn't been probed
+func (t *Prober) Health(provider string) (ProviderHealth, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	s, ok := t.states[provider]
+	if !ok || s.health.LastProbe.IsZero() {
+		return ProviderHealth{}, false
+	}
+	return s.health, true
+}
+
+// Recommended returns the best healthy provider or "" if none is healthy
+func (t *Prober) Recommended() string {
+	providers := t.Providers()
+	if len(providers) == 0 || !providers[0].Healthy {
+		return ""
+	}
+	return providers[0].Url
+}
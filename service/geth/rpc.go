@@ -1,15 +1,43 @@
 package geth
 
 import (
+	"context"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/config"
-	"github.com/ybbus/jsonrpc"
-	"strconv"
-	"strings"
+	"github.com/opendexnetwork/opendex-docker-api/rpc"
+	"net/http"
 )
 
+// ReadOnlyMethods are forwarded by /api/v1/geth/jsonrpc by default
+var ReadOnlyMethods = []string{
+	"eth_blockNumber",
+	"eth_call",
+	"eth_chainId",
+	"eth_estimateGas",
+	"eth_gasPrice",
+	"eth_getBalance",
+	"eth_getBlockByHash",
+	"eth_getBlockByNumber",
+	"eth_getBlockTransactionCountByHash",
+	"eth_getBlockTransactionCountByNumber",
+	"eth_getCode",
+	"eth_getLogs",
+	"eth_getStorageAt",
+	"eth_getTransactionByBlockHashAndIndex",
+	"eth_getTransactionByBlockNumberAndIndex",
+	"eth_getTransactionByHash",
+	"eth_getTransactionCount",
+	"eth_getTransactionReceipt",
+	"eth_protocolVersion",
+	"eth_syncing",
+	"net_listening",
+	"net_peerCount",
+	"net_version",
+	"web3_clientVersion",
+}
+
 type RpcClient struct {
-	client jsonrpc.RPCClient
+	client *rpc.JsonRpcClient
 }
 
 func NewRpcClient(config config.RpcConfig) *RpcClient {
@@ -17,94 +45,97 @@ func NewRpcClient(config config.RpcConfig) *RpcClient {
 	port := uint16(config["port"].(float64))
 
 	addr := fmt.Sprintf("http://%s:%d", host, port)
-	client := jsonrpc.NewClientWithOpts(addr, &jsonrpc.RPCClientOpts{})
+	client := rpc.NewJsonRpcClient(addr, &http.Client{}, nil, ReadOnlyMethods)
 
 	return &RpcClient{
 		client: client,
 	}
 }
 
+// GetJsonRpcClient returns the client of the JSON-RPC passthrough
+func (t *RpcClient) GetJsonRpcClient() *rpc.JsonRpcClient {
+	return t.client
+}
+
 type Syncing struct {
-	CurrentBlock  int64
-	HighestBlock  int64
-	KnownStates   int64
-	PulledStates  int64
-	StartingBlock int64
+	CurrentBlock  int64 `json:"currentBlock"`
+	HighestBlock  int64 `json:"highestBlock"`
+	KnownStates   int64 `json:"knownStates"`
+	PulledStates  int64 `json:"pulledStates"`
+	StartingBlock int64 `json:"startingBlock"`
 }
 
+// parseHex parses a hex quantity. The state counters of mainnet don't fit in 32 bits.
 func parseHex(value string) (int64, error) {
-	value = strings.Replace(value, "0x", "", 1)
-	i64, err := strconv.ParseInt(value, 16, 32)
+	n, err := parseQuantity(value)
 	if err != nil {
 		return 0, err
 	}
-	return i64, nil
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("value out of range: %s", value)
+	}
+	return n.Int64(), nil
 }
 
-func (t *RpcClient) EthSyncing() (*Syncing, error) {
-	result, err := t.client.Call("eth_syncing")
-	if err != nil {
+// ethSyncing returns the eth_syncing result of a client or nil if the node isn't syncing
+func ethSyncing(ctx context.Context, client *rpc.JsonRpcClient) (*Syncing, error) {
+	var result interface{}
+	if err := client.CallFor(ctx, &result, "eth_syncing"); err != nil {
 		return nil, err
 	}
-
-	var syncing map[string]string
-	err = result.GetObject(&syncing)
-	if err != nil {
-		_, err := result.GetBool()
-		if err != nil {
-			return nil, err
+	syncing, ok := result.(map[string]interface{})
+	if !ok {
+		if _, ok := result.(bool); ok {
+			return nil, nil
 		}
-		return nil, nil
-	}
-
-	currentBlock, err := parseHex(syncing["currentBlock"])
-	if err != nil {
-		return nil, err
-	}
-
-	highestBlock, err := parseHex(syncing["highestBlock"])
-	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid eth_syncing result: %v", result)
 	}
 
-	knownStates, err := parseHex(syncing["knownStates"])
-	if err != nil {
-		return nil, err
+	r := &Syncing{}
+	fields := []struct {
+		name string
+		// the state counters are gone since geth 1.11 (snap sync)
+		optional bool
+		value    *int64
+	}{
+		{"currentBlock", false, &r.CurrentBlock},
+		{"highestBlock", false, &r.HighestBlock},
+		{"knownStates", true, &r.KnownStates},
+		{"pulledStates", true, &r.PulledStates},
+		{"startingBlock", true, &r.StartingBlock},
 	}
-
-	pulledStates, err := parseHex(syncing["pulledStates"])
-	if err != nil {
-		return nil, err
+	for _, f := range fields {
+		s, ok := syncing[f.name].(string)
+		if !ok {
+			if f.optional {
+				continue
+			}
+			return nil, fmt.Errorf("eth_syncing has no %s", f.name)
+		}
+		n, err := parseHex(s)
+		if err != nil {
+			return nil, fmt.Errorf("eth_syncing %s: %s", f.name, err)
+		}
+		*f.value = n
 	}
+	return r, nil
+}
 
-	startingBlock, err := parseHex(syncing["startingBlock"])
-	if err != nil {
-		return nil, err
+// ethQuantity calls a method with a hex quantity result like eth_blockNumber
+func ethQuantity(ctx context.Context, client *rpc.JsonRpcClient, method string) (int64, error) {
+	var result string
+	if err := client.CallFor(ctx, &result, method); err != nil {
+		return 0, err
 	}
+	return parseHex(result)
+}
 
-	return &Syncing{
-		CurrentBlock:  currentBlock,
-		HighestBlock:  highestBlock,
-		KnownStates:   knownStates,
-		PulledStates:  pulledStates,
-		StartingBlock: startingBlock,
-	}, nil
+func (t *RpcClient) EthSyncing(ctx context.Context) (*Syncing, error) {
+	return ethSyncing(ctx, t.client)
 }
 
-func (t *RpcClient) EthBlockNumber() (int64, error) {
-	result, err := t.client.Call("eth_blockNumber")
-	if err != nil {
-		return 0, err
-	}
-	s, err := result.GetString()
-	if err != nil {
-		return 0, err
-	}
-	blockNumber, err := parseHex(s)
-	if err != nil {
-		return 0, err
-	}
-	return blockNumber, nil
+func (t *RpcClient) EthBlockNumber(ctx context.Context) (int64, error) {
+	return ethQuantity(ctx, t.client, "eth_blockNumber")
 }
 
 func explainNetVersion(version string) string {
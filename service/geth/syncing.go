@@ -0,0 +1,58 @@
+package geth
+
+import (
+	"context"
+)
+
+type SyncState struct {
+	Mode     Mode   `json:"mode"`
+	Provider string `json:"provider"`
+	Syncing  bool   `json:"syncing"`
+	// CurrentBlock and HighestBlock are the block number when the node isn't syncing
+	StartingBlock int64 `json:"startingBlock"`
+	CurrentBlock  int64 `json:"currentBlock"`
+	HighestBlock  int64 `json:"highestBlock"`
+	// KnownStates and PulledStates are 0 on geth 1.11 and later
+	KnownStates   int64  `json:"knownStates"`
+	PulledStates  int64  `json:"pulledStates"`
+	Peers         int64  `json:"peers"`
+	ClientVersion string `json:"clientVersion"`
+}
+
+// GetSyncState returns the eth_syncing state, the peer count and the client version of the provider of the current
+// mode
+func (t *Service) GetSyncState(ctx context.Context) (*SyncState, error) {
+	client, mode, provider, err := t.ethClient()
+	if err != nil {
+		return nil, err
+	}
+	result := &SyncState{Mode: mode, Provider: provider}
+
+	syncing, err := ethSyncing(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	if syncing != nil {
+		result.Syncing = true
+		result.StartingBlock = syncing.StartingBlock
+		result.CurrentBlock = syncing.CurrentBlock
+		result.HighestBlock = syncing.HighestBlock
+		result.KnownStates = syncing.KnownStates
+		result.PulledStates = syncing.PulledStates
+	} else {
+		blockNumber, err := ethQuantity(ctx, client, "eth_blockNumber")
+		if err != nil {
+			return nil, err
+		}
+		result.CurrentBlock = blockNumber
+		result.HighestBlock = blockNumber
+	}
+
+	if result.Peers, err = ethQuantity(ctx, client, "net_peerCount"); err != nil {
+		return nil, err
+	}
+	if result.ClientVersion, err = callString(ctx, client, "web3_clientVersion"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -2,10 +2,10 @@ package service
 
 import (
 	"bufio"
-	"fmt"
 	"github.com/sirupsen/logrus"
 	"os/exec"
 	"strings"
+	"sync"
 )
 
 type SetupStatus struct {
@@ -20,12 +20,14 @@ type LauncherAgent struct {
 	logger        *logrus.Entry
 	statusHistory []SetupStatus
 	state         string
+	cmd           *exec.Cmd
+	mutex         sync.Mutex
 }
 
-func NewLauncherAgent(network string, logger *logrus.Entry) *LauncherAgent {
+func NewLauncherAgent(logfile string, logger *logrus.Entry) *LauncherAgent {
 	a := &LauncherAgent{
 		listeners:     []chan SetupStatus{},
-		logfile:       fmt.Sprintf("/root/network/logs/%s.log", network),
+		logfile:       logfile,
 		running:       true,
 		logger:        logger,
 		statusHistory: []SetupStatus{},
@@ -42,6 +44,14 @@ func (t *LauncherAgent) followLog() {
 	r, _ := c.StdoutPipe()
 	c.Stderr = c.Stdout
 
+	t.mutex.Lock()
+	if !t.running {
+		t.mutex.Unlock()
+		return
+	}
+	t.cmd = c
+	t.mutex.Unlock()
+
 	go func() {
 		t.state = "attached"
 		scanner := bufio.NewScanner(r)
@@ -53,11 +63,17 @@ func (t *LauncherAgent) followLog() {
 	}()
 
 	err := c.Run()
-	if err != nil {
+	if err != nil && t.isRunning() {
 		t.logger.Errorf("Failed to tail %s: %s", t.logfile, err)
 	}
 }
 
+func (t *LauncherAgent) isRunning() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.running
+}
+
 func (t *LauncherAgent) handleLine(line string) {
 	if strings.Contains(line, "Waiting for XUD dependencies to be ready") {
 		t.state = "setup"
@@ -104,15 +120,27 @@ func (t *LauncherAgent) handleLine(line string) {
 
 func (t *LauncherAgent) emitStatus(status SetupStatus) {
 	t.logger.Debugf("Emit %s", status)
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
 	t.statusHistory = append(t.statusHistory, status)
+	// never block the log follower (and Close) on a slow subscriber; the channels are buffered
 	for _, listener := range t.listeners {
-		listener <- status
+		select {
+		case listener <- status:
+		default:
+			t.logger.Warnf("Dropped setup status %q for a slow subscriber", status.Status)
+		}
 	}
 }
 
 func (t *LauncherAgent) subscribeSetupStatus(history int) (<-chan SetupStatus, func(), []SetupStatus) {
 	ch := make(chan SetupStatus, 100)
-	t.listeners = append(t.listeners, ch)
+	t.mutex.Lock()
+	if t.running {
+		t.listeners = append(t.listeners, ch)
+	} else {
+		close(ch)
+	}
 
 	var h []SetupStatus
 
@@ -125,8 +153,11 @@ func (t *LauncherAgent) subscribeSetupStatus(history int) (<-chan SetupStatus, f
 	} else if history == -1 {
 		h = append(h, t.statusHistory...)
 	}
+	t.mutex.Unlock()
 
 	var cancel = func() {
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
 		for i, listener := range t.listeners {
 			if listener == ch {
 				if i+1 >= len(t.listeners) {
@@ -142,6 +173,29 @@ func (t *LauncherAgent) subscribeSetupStatus(history int) (<-chan SetupStatus, f
 	return ch, cancel, h
 }
 
+// Close stops following the launcher log. The subscribers get a final "Shutdown" status before their channels are
+// closed.
+func (t *LauncherAgent) Close() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if !t.running {
+		return
+	}
+	t.running = false
+	if t.cmd != nil && t.cmd.Process != nil {
+		_ = t.cmd.Process.Kill()
+	}
+	status := SetupStatus{Status: "Shutdown", Details: nil}
+	for _, listener := range t.listeners {
+		select {
+		case listener <- status:
+		default:
+		}
+		close(listener)
+	}
+	t.listeners = nil
+}
+
 func (t *LauncherAgent) GetState() string {
 	return t.state
 }
@@ -18,8 +18,9 @@ func New(
 	dockerClient *docker.Client,
 	l2ServiceName string,
 	rpcConfig config.RpcConfig,
+	resolvePath func(path string) string,
 ) *Service {
 	return &Service{
-		bitcoind.New(name, services, containerName, dockerClient, l2ServiceName, rpcConfig),
+		bitcoind.New(name, services, containerName, dockerClient, l2ServiceName, rpcConfig, resolvePath),
 	}
 }
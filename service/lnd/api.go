@@ -3,6 +3,8 @@ package lnd
 import (
 	"context"
 	"fmt"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
+	pb "github.com/opendexnetwork/opendex-docker-api/service/lnd/lnrpc"
 	"github.com/opendexnetwork/opendex-docker-api/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/protobuf/jsonpb"
@@ -10,6 +12,19 @@ import (
 	"time"
 )
 
+func (t *Service) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/getinfo", t.GetName()), "Get general information about the node").
+		Tag(t.GetName()).Returns(&pb.GetInfoResponse{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/config", t.GetName()), "Get the settings of lnd.conf").
+		Tag(t.GetName()).
+		Describe("Returns the chain backend (neutrino, bitcoind or litecoind), the network, the RPC host and ZMQ endpoints of a full node backend, the alias, color and listen addresses.").
+		Returns(Config{})
+	spec.Route(http.MethodGet, fmt.Sprintf("/api/v1/%s/sync", t.GetName()), "Get the neutrino sync progress").
+		Tag(t.GetName()).
+		Describe("Returns the cfheaders height, the block height neutrino syncs to and the last block height of lnd, followed in the logs of the container.").
+		Returns(SyncState{})
+}
+
 func (t *Service) ConfigureRouter(r *gin.RouterGroup) {
 	r.GET(fmt.Sprintf("/v1/%s/getinfo", t.GetName()), func(c *gin.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), 3 * time.Second)
@@ -27,4 +42,21 @@ func (t *Service) ConfigureRouter(r *gin.RouterGroup) {
 		}
 		c.Header("Content-Type", "application/json; charset=utf-8")
 	})
+
+	r.GET(fmt.Sprintf("/v1/%s/config", t.GetName()), func(c *gin.Context) {
+		resp, err := t.GetConfig()
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	})
+
+	r.GET(fmt.Sprintf("/v1/%s/sync", t.GetName()), func(c *gin.Context) {
+		if !t.Neutrino() {
+			utils.JsonError(c, "lnd is not in neutrino mode", http.StatusConflict)
+			return
+		}
+		c.JSON(http.StatusOK, t.logWatcher.GetSyncState())
+	})
 }
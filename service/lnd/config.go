@@ -0,0 +1,101 @@
+package lnd
+
+import (
+	"fmt"
+)
+
+const (
+	BackendNeutrino  = "neutrino"
+	BackendBitcoind  = "bitcoind"
+	BackendLitecoind = "litecoind"
+	BackendBtcd      = "btcd"
+	BackendLtcd      = "ltcd"
+)
+
+var (
+	// networks are the network options of lnd.conf
+	networks = []string{"mainnet", "testnet", "regtest", "simnet"}
+)
+
+// Config is the typed model of lnd.conf
+type Config struct {
+	// Backend is the chain backend: neutrino, bitcoind or litecoind (btcd or ltcd on simnet)
+	Backend string `json:"backend"`
+	Network string `json:"network"`
+	// RpcHost, ZmqPubRawBlock and ZmqPubRawTx are the settings of a full node backend
+	RpcHost        string `json:"rpcHost,omitempty"`
+	ZmqPubRawBlock string `json:"zmqPubRawBlock,omitempty"`
+	ZmqPubRawTx    string `json:"zmqPubRawTx,omitempty"`
+	// NeutrinoPeers are the neutrino.connect and neutrino.addpeer peers
+	NeutrinoPeers []string `json:"neutrinoPeers,omitempty"`
+	Alias         string   `json:"alias"`
+	Color         string   `json:"color"`
+	Listen        []string `json:"listen"`
+	RpcListen     []string `json:"rpcListen"`
+	RestListen    []string `json:"restListen"`
+}
+
+// configValues returns the values of a key of lnd.conf or none if it is missing
+func (t *Service) configValues(key string) []string {
+	values, err := t.GetConfigValues(key)
+	if err != nil {
+		return []string{}
+	}
+	return values
+}
+
+// GetConfigValue returns the value of a single-valued key of lnd.conf. Like lnd, the last one wins when the key is
+// repeated.
+func (t *Service) GetConfigValue(key string) (string, error) {
+	values, err := t.GetConfigValues(key)
+	if err != nil {
+		return "", err
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("%s is not set in lnd.conf", key)
+	}
+	return values[len(values)-1], nil
+}
+
+// configValue returns the value of a key of lnd.conf or "" if it is missing
+func (t *Service) configValue(key string) string {
+	value, err := t.GetConfigValue(key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// GetConfig parses lnd.conf
+func (t *Service) GetConfig() (*Config, error) {
+	if _, err := t.loadConfFile(); err != nil {
+		return nil, err
+	}
+	backend, err := t.GetBackendNode()
+	if err != nil {
+		return nil, fmt.Errorf("%s.node is not set in lnd.conf", t.chain)
+	}
+
+	result := &Config{
+		Backend:    backend,
+		Alias:      t.configValue("alias"),
+		Color:      t.configValue("color"),
+		Listen:     t.configValues("listen"),
+		RpcListen:  t.configValues("rpclisten"),
+		RestListen: t.configValues("restlisten"),
+	}
+	for _, network := range networks {
+		value := t.configValue(fmt.Sprintf("%s.%s", t.chain, network))
+		if value == "1" || value == "true" {
+			result.Network = network
+		}
+	}
+	if backend == BackendNeutrino {
+		result.NeutrinoPeers = append(t.configValues("neutrino.connect"), t.configValues("neutrino.addpeer")...)
+	} else {
+		result.RpcHost = t.configValue(backend + ".rpchost")
+		result.ZmqPubRawBlock = t.configValue(backend + ".zmqpubrawblock")
+		result.ZmqPubRawTx = t.configValue(backend + ".zmqpubrawtx")
+	}
+	return result, nil
+}
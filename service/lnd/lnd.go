@@ -2,30 +2,33 @@ package lnd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/config"
 	"github.com/opendexnetwork/opendex-docker-api/service/core"
 	docker "github.com/docker/docker/client"
 	"gopkg.in/ini.v1"
 	"io/ioutil"
+	"path/filepath"
 	"strings"
 )
 
+const (
+	applicationOptions = "Application Options"
+)
+
 type Service struct {
 	*core.SingleContainerService
 	*RpcClient
 
 	chain      string
+	dataDir    string
 	logWatcher *LogWatcher
 }
 
 func (t *Service) GetBackendNode() (string, error) {
 	key := fmt.Sprintf("%s.node", t.chain)
-	values, err := t.GetConfigValues(key)
-	if err != nil {
-		return "", err
-	}
-	return values[0], err
+	return t.GetConfigValue(key)
 }
 
 func New(
@@ -35,6 +38,7 @@ func New(
 	dockerClient *docker.Client,
 	chain string,
 	rpcConfig config.RpcConfig,
+	dataDir string,
 ) *Service {
 
 	base := core.NewSingleContainerService(name, services, containerName, dockerClient)
@@ -46,6 +50,7 @@ func New(
 		SingleContainerService: base,
 		RpcClient:              rpcClient,
 		chain:                  chain,
+		dataDir:                dataDir,
 		logWatcher:             logWatcher,
 	}
 
@@ -55,7 +60,7 @@ func New(
 }
 
 func (t *Service) loadConfFile() (string, error) {
-	confFile := fmt.Sprintf("/root/network/data/%s/lnd.conf", t.GetName())
+	confFile := filepath.Join(t.dataDir, "lnd.conf")
 	content, err := ioutil.ReadFile(confFile)
 	if err != nil {
 		return "", err
@@ -77,8 +82,13 @@ func (t *Service) GetConfigValues(key string) ([]string, error) {
 	//}
 
 	conf, err := t.loadConfFile()
+	if err != nil {
+		return result, err
+	}
 
-	config, err := ini.ShadowLoad([]byte(conf))
+	// lnd keeps the # of color=#3399ff and, like lnd, the section names are case-insensitive ([Neutrino] or
+	// [neutrino])
+	config, err := ini.LoadSources(ini.LoadOptions{Insensitive: true, AllowShadows: true, IgnoreInlineComment: true}, []byte(conf))
 	if err != nil {
 		return result, err
 	}
@@ -86,7 +96,7 @@ func (t *Service) GetConfigValues(key string) ([]string, error) {
 	parts := strings.Split(key, ".")
 
 	if cap(parts) == 2 {
-		section, err := config.GetSection(strings.Title(parts[0]))
+		section, err := config.GetSection(parts[0])
 		if err != nil {
 			return result, err
 		}
@@ -95,8 +105,8 @@ func (t *Service) GetConfigValues(key string) ([]string, error) {
 		if err != nil {
 			return result, err
 		}
-		value := iniKey.Value()
-		result = append(result, value)
+		values := iniKey.ValueWithShadows()
+		result = append(result, values...)
 	} else if cap(parts) == 1 {
 		section, err := config.GetSection(ini.DefaultSection)
 		if err != nil {
@@ -105,7 +115,15 @@ func (t *Service) GetConfigValues(key string) ([]string, error) {
 
 		iniKey, err := section.GetKey(key)
 		if err != nil {
-			return result, err
+			// the general options may be in [Application Options] like in the sample lnd.conf
+			section, err = config.GetSection(applicationOptions)
+			if err != nil {
+				return result, err
+			}
+			iniKey, err = section.GetKey(key)
+			if err != nil {
+				return result, err
+			}
 		}
 		values := iniKey.ValueWithShadows()
 		result = append(result, values...)
@@ -114,9 +132,13 @@ func (t *Service) GetConfigValues(key string) ([]string, error) {
 	return result, nil
 }
 
+// Neutrino tells if lnd.conf has the neutrino backend
 func (t *Service) Neutrino() bool {
-	// TODO get lnd backend type
-	return true
+	backend, err := t.GetBackendNode()
+	if err != nil {
+		return false
+	}
+	return backend == BackendNeutrino
 }
 
 func syncingText(current int64, total int64) string {
@@ -146,27 +168,49 @@ func (t *Service) GetStatus(ctx context.Context) string {
 
 	// container is running
 
+	backend, err := t.GetBackendNode()
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err)
+	}
+	neutrino := backend == BackendNeutrino
+
 	info, err := t.GetInfo(ctx)
 	if err != nil {
 		if strings.Contains(err.Error(), "Wallet is encrypted") {
 			return "Wallet locked. Unlock with lncli unlock."
 		} else if strings.Contains(err.Error(), "no such file or directory") {
-			if t.Neutrino() {
+			if neutrino {
 				return t.logWatcher.GetNeutrinoStatus()
 			}
+			return "Waiting for wallet creation"
 		} else if strings.Contains(err.Error(), "no client") {
-			if t.Neutrino() {
+			if neutrino {
 				return t.logWatcher.GetNeutrinoStatus()
 			}
+			return fmt.Sprintf("Waiting for %s to come up...", t.GetName())
 		} else if strings.Contains(err.Error(), "rpc error: code = Unimplemented desc = unknown service lnrpc.Lightning") {
-			if t.Neutrino() {
+			if neutrino {
 				return t.logWatcher.GetNeutrinoStatus()
 			}
+			return "Waiting for wallet creation"
 		}
 		return fmt.Sprintf("Error: %s", err)
 	}
 
 	syncedToChain := info.SyncedToChain
+
+	if !neutrino {
+		if syncedToChain {
+			return "Ready"
+		}
+		// lnd follows the blocks of its full node backend
+		total, err := t.backendHeight(ctx)
+		if err == nil && int64(info.BlockHeight) < total {
+			return fmt.Sprintf("Syncing %.2f%% (%d/%d)", float32(info.BlockHeight)/float32(total)*100.0, info.BlockHeight, total)
+		}
+		return "Syncing"
+	}
+
 	total := info.BlockHeight
 	current, err := t.logWatcher.getCurrentHeight()
 
@@ -187,7 +231,56 @@ func (t *Service) GetStatus(ctx context.Context) string {
 	}
 }
 
+// backendHeight returns the block height of the full node backend of lnd
+func (t *Service) backendHeight(ctx context.Context) (int64, error) {
+	backend, err := t.GetBackendNode()
+	if err != nil {
+		return 0, err
+	}
+	// the bitcoind and litecoind services report the external node too
+	s, ok := t.GetService(backend).(core.Syncer)
+	if !ok {
+		return 0, fmt.Errorf("no %s service", backend)
+	}
+	current, _, err := s.GetSyncHeights(ctx)
+	return current, err
+}
+
+// GetSyncHeights returns the neutrino sync heights before the wallet is created and then the block height of lnd
+// against the best block of the chain. With a full node backend it's the block height of lnd against the blocks of
+// the node.
+func (t *Service) GetSyncHeights(ctx context.Context) (int64, int64, error) {
+	neutrino := t.Neutrino()
+	info, err := t.GetInfo(ctx)
+	if !neutrino {
+		if err != nil {
+			return 0, 0, err
+		}
+		total, err := t.backendHeight(ctx)
+		if err != nil {
+			return 0, 0, err
+		}
+		return int64(info.BlockHeight), total, nil
+	}
+	if err != nil {
+		current, total := t.logWatcher.GetNeutrinoHeights()
+		if total == 0 {
+			return 0, 0, err
+		}
+		return current, total, nil
+	}
+	current, err := t.logWatcher.getCurrentHeight()
+	if err != nil || current == 0 {
+		if info.SyncedToChain {
+			return int64(info.BlockHeight), int64(info.BlockHeight), nil
+		}
+		return 0, 0, errors.New("unknown block height")
+	}
+	return int64(current), int64(info.BlockHeight), nil
+}
+
 func (t *Service) Close() error {
+	t.logWatcher.Stop()
 	err := t.RpcClient.Close()
 	if err != nil {
 		t.GetLogger().Errorf("Failed to close RPC client: %s", err)
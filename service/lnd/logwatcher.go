@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,15 +17,31 @@ type NeutrinoSyncing struct {
 	done    bool
 }
 
+// SyncState is the neutrino sync progress of lnd followed in its logs
+type SyncState struct {
+	// FilterHeaders is the cfheaders height and BlockHeight the height of the peer neutrino syncs to
+	FilterHeaders int64   `json:"filterHeaders"`
+	BlockHeight   int64   `json:"blockHeight"`
+	Progress      float64 `json:"progress"`
+	// Synced is set when neutrino is caught up with the cfheaders
+	Synced bool `json:"synced"`
+	// Height is the height of the last block lnd was notified of, 0 until the wallet is unlocked
+	Height int64 `json:"height"`
+}
+
 type LogWatcher struct {
 	p               *regexp.Regexp
 	p0              *regexp.Regexp
 	p1              *regexp.Regexp
 	p2              *regexp.Regexp
 	neutrinoSyncing NeutrinoSyncing
-	logger          *logrus.Entry
-	service         *core.SingleContainerService
-	stop            func()
+	// height is the last "New block" height
+	height  int64
+	logger  *logrus.Entry
+	service *core.SingleContainerService
+	stop    func()
+	// mutex guards neutrinoSyncing, height and stop which Start updates while the status is read
+	mutex *sync.RWMutex
 }
 
 func initRegex(containerName string) (*regexp.Regexp, *regexp.Regexp, *regexp.Regexp, *regexp.Regexp) {
@@ -70,6 +87,7 @@ func NewLogWatcher(containerName string, service *core.SingleContainerService) *
 		neutrinoSyncing: NeutrinoSyncing{current: 0, total: 0, done: false},
 		logger:          service.GetLogger().WithField("name", fmt.Sprintf("service.%s.logwatcher", service.GetName())),
 		service:         service,
+		mutex:           &sync.RWMutex{},
 	}
 	return w
 }
@@ -77,18 +95,24 @@ func NewLogWatcher(containerName string, service *core.SingleContainerService) *
 func (t *LogWatcher) getLogs() <-chan string {
 	for {
 		lines, stop, err := t.service.FollowLogs2()
-		t.stop = stop
 		if err != nil {
-			t.logger.Error("Failed to follow logs: %s", err)
+			t.logger.Errorf("Failed to follow logs: %s", err)
 			time.Sleep(3 * time.Second)
+			continue
 		}
+		t.mutex.Lock()
+		t.stop = stop
+		t.mutex.Unlock()
 		return lines
 	}
 }
 
 func (t *LogWatcher) stopFollowing() {
-	if t.stop != nil {
-		t.stop()
+	t.mutex.RLock()
+	stop := t.stop
+	t.mutex.RUnlock()
+	if stop != nil {
+		stop()
 	}
 }
 
@@ -101,39 +125,41 @@ func (t *LogWatcher) getNumber(p *regexp.Regexp, line string) int64 {
 	return n
 }
 
+// handleLine updates the sync state from a log line
+func (t *LogWatcher) handleLine(line string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.p.MatchString(line) {
+		t.height = t.getNumber(t.p, line)
+	} else if t.p0.MatchString(line) {
+		t.neutrinoSyncing.current = t.getNumber(t.p0, line)
+		if t.neutrinoSyncing.current < t.neutrinoSyncing.total {
+			t.neutrinoSyncing.current = t.neutrinoSyncing.total
+		} else if t.neutrinoSyncing.current > t.neutrinoSyncing.total {
+			t.neutrinoSyncing.total = t.neutrinoSyncing.current
+		}
+		t.neutrinoSyncing.done = true
+	} else if t.p1.MatchString(line) {
+		t.neutrinoSyncing.current = t.getNumber(t.p1, line)
+	} else if t.p2.MatchString(line) {
+		t.neutrinoSyncing.total = t.getNumber(t.p2, line)
+	} else if line == "--- EOF ---" {
+		t.logger.Debugf("Reset Neutrino syncing state")
+		t.neutrinoSyncing.current = 0
+		t.neutrinoSyncing.total = 0
+		t.neutrinoSyncing.done = false
+		t.height = 0
+	}
+}
+
 func (t *LogWatcher) Start() {
 
 	t.logger.Debug("Starting")
 
 	lines := t.getLogs()
 	for line := range lines {
-
-		line = strings.TrimSpace(line)
-
-		if t.p0.MatchString(line) {
-			//t.logger.Debugf("*** %s", line)
-			t.neutrinoSyncing.current = t.getNumber(t.p0, line)
-			//t.logger.Debugf("current=%d", t.neutrinoSyncing.current)
-			if t.neutrinoSyncing.current < t.neutrinoSyncing.total {
-				t.neutrinoSyncing.current = t.neutrinoSyncing.total
-			} else if t.neutrinoSyncing.current > t.neutrinoSyncing.total {
-				t.neutrinoSyncing.total = t.neutrinoSyncing.current
-			}
-			t.neutrinoSyncing.done = true
-		} else if t.p1.MatchString(line) {
-			//t.logger.Debugf("*** %s", line)
-			t.neutrinoSyncing.current = t.getNumber(t.p1, line)
-			//t.logger.Debugf("current=%d", t.neutrinoSyncing.current)
-		} else if t.p2.MatchString(line) {
-			//t.logger.Debugf("*** %s", line)
-			t.neutrinoSyncing.total = t.getNumber(t.p2, line)
-			//t.logger.Debugf("total=%d", t.neutrinoSyncing.total)
-		} else if line == "--- EOF ---" {
-			t.logger.Debugf("Reset Neutrino syncing state")
-			t.neutrinoSyncing.current = 0
-			t.neutrinoSyncing.total = 0
-			t.neutrinoSyncing.done = false
-		}
+		t.handleLine(strings.TrimSpace(line))
 	}
 
 	t.stopFollowing()
@@ -142,9 +168,30 @@ func (t *LogWatcher) Start() {
 
 }
 
+// GetNeutrinoHeights returns the cfheaders height and the block height neutrino syncs to
+func (t *LogWatcher) GetNeutrinoHeights() (int64, int64) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.neutrinoSyncing.current, t.neutrinoSyncing.total
+}
+
+func (t *LogWatcher) GetSyncState() SyncState {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	result := SyncState{
+		FilterHeaders: t.neutrinoSyncing.current,
+		BlockHeight:   t.neutrinoSyncing.total,
+		Synced:        t.neutrinoSyncing.done,
+		Height:        t.height,
+	}
+	if result.BlockHeight > 0 {
+		result.Progress = float64(result.FilterHeaders) / float64(result.BlockHeight) * 100
+	}
+	return result
+}
+
 func (t *LogWatcher) GetNeutrinoStatus() string {
-	current := t.neutrinoSyncing.current
-	total := t.neutrinoSyncing.total
+	current, total := t.GetNeutrinoHeights()
 	return syncingText(current, total)
 }
 
@@ -152,27 +199,9 @@ func (t *LogWatcher) Stop() {
 	t.stopFollowing()
 }
 
+// getCurrentHeight returns the height of the last block lnd was notified of since the container started
 func (t *LogWatcher) getCurrentHeight() (uint32, error) {
-	logs, err := t.service.GetLogs("10m", "all")
-	if err != nil {
-		return 0, nil
-	}
-
-	var height string
-
-	for _, line := range logs {
-		if t.p.MatchString(line) {
-			height = t.p.ReplaceAllString(line, "$1")
-		}
-	}
-
-	if height != "" {
-		i64, err := strconv.ParseInt(height, 10, 32)
-		if err != nil {
-			return 0, nil
-		}
-		return uint32(i64), nil
-	}
-
-	return 0, nil
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return uint32(t.height), nil
 }
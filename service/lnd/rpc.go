@@ -67,3 +67,8 @@ func (t *RpcClient) GetInfo(ctx context.Context) (*pb.GetInfoResponse, error) {
 	req := pb.GetInfoRequest{}
 	return client.GetInfo(ctx, &req)
 }
+
+// GrpcConns returns the connection of the service for the gRPC gateway
+func (t *RpcClient) GrpcConns() map[string]*rpc.GrpcConn {
+	return map[string]*rpc.GrpcConn{"": t.conn}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/rpc"
 	"github.com/opendexnetwork/opendex-docker-api/service/arby"
 	"github.com/opendexnetwork/opendex-docker-api/service/bitcoind"
 	"github.com/opendexnetwork/opendex-docker-api/service/boltz"
@@ -21,6 +22,7 @@ import (
 	docker "github.com/docker/docker/client"
 	"github.com/sirupsen/logrus"
 	"io/ioutil"
+	"sync"
 )
 
 var (
@@ -38,33 +40,44 @@ var (
 	}
 )
 
+// jsonRpcService is implemented by the services with a JSON-RPC passthrough
+type jsonRpcService interface {
+	GetJsonRpcClient() *rpc.JsonRpcClient
+}
+
 type Manager struct {
-	network   string
+	config    *config.Config
 	services  []core.Service
 	factory   core.DockerClientFactory
 	logger    *logrus.Entry
 	listeners map[string]core.DockerEventListener
 
-	*LauncherAgent
-}
+	// stopEvents stops listening for Docker events and sampling the sync heights
+	stopEvents context.CancelFunc
 
-func containerName(network string, service string) string {
-	return fmt.Sprintf("%s_%s_1", network, service)
+	syncTrackers map[string]*core.SyncTracker
+
+	// settingsMutex serializes the changes of config.json
+	settingsMutex *sync.Mutex
+
+	*LauncherAgent
 }
 
-func initServices(network string, dockerClient *docker.Client, listeners map[string]core.DockerEventListener) []core.Service {
+func initServices(cfg *config.Config, dockerClient *docker.Client, listeners map[string]core.DockerEventListener) ([]core.Service, error) {
 
-	f, err := ioutil.ReadFile("/root/network/data/config.json")
+	f, err := ioutil.ReadFile(cfg.ServicesConfig)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	var config map[string]interface{}
-	err = json.Unmarshal(f, &config)
+	var servicesConfig map[string]interface{}
+	err = json.Unmarshal(f, &servicesConfig)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("invalid %s: %s", cfg.ServicesConfig, err)
 	}
 
+	network := cfg.Network
+
 	var result []core.Service
 	var resultMap = make(map[string]core.Service)
 	var s core.Service
@@ -74,12 +87,15 @@ func initServices(network string, dockerClient *docker.Client, listeners map[str
 	var disabled bool
 	var mode string
 
-	for _, item := range config["services"].([]interface{}) {
+	for _, item := range servicesConfig["services"].([]interface{}) {
 		x := item.(map[string]interface{})
 
 		name = x["name"].(string)
-		cName = containerName(network, name)
+		cName = cfg.ContainerName(name)
 		rpc = x["rpc"].(map[string]interface{})
+		if err := cfg.ResolveRpcConfig(name, rpc); err != nil {
+			return nil, err
+		}
 		disabled = x["disabled"].(bool)
 		if x["mode"] == nil {
 			mode = ""
@@ -88,19 +104,20 @@ func initServices(network string, dockerClient *docker.Client, listeners map[str
 		}
 		switch name {
 		case "bitcoind":
-			s = bitcoind.New(name, resultMap, cName, dockerClient, "lndbtc", rpc)
+			s = bitcoind.New(name, resultMap, cName, dockerClient, "lndbtc", rpc, cfg.ResolvePath)
 		case "litecoind":
-			s = litecoind.New(name, resultMap, cName, dockerClient, "lndltc", rpc)
+			s = litecoind.New(name, resultMap, cName, dockerClient, "lndltc", rpc, cfg.ResolvePath)
 		case "geth":
-			s = geth.New(name, resultMap, cName, dockerClient, "connext", lightProviders[network], rpc)
+			chain, _ := connext.ChainOf(network)
+			s = geth.New(name, resultMap, cName, dockerClient, "connext", lightProviders[network], chain.Id, cfg.EthFailover, cfg.EthTokens, rpc)
 		case "lndbtc":
-			s = lnd.New(name, resultMap, cName, dockerClient, "bitcoin", rpc)
+			s = lnd.New(name, resultMap, cName, dockerClient, "bitcoin", rpc, cfg.ServiceDataDir(name))
 		case "lndltc":
-			s = lnd.New(name, resultMap, cName, dockerClient, "litecoin", rpc)
+			s = lnd.New(name, resultMap, cName, dockerClient, "litecoin", rpc, cfg.ServiceDataDir(name))
 		case "connext":
-			s = connext.New(name, resultMap, cName, dockerClient, rpc)
+			s = connext.New(name, resultMap, cName, dockerClient, network, rpc)
 		case "opendexd":
-			s = opendexd.New(name, resultMap, cName, dockerClient, rpc)
+			s = opendexd.New(name, resultMap, cName, dockerClient, rpc, cfg.ServiceDataDir(name), cfg.PasswordUnsetFile())
 		case "arby":
 			s = arby.New(name, resultMap, cName, dockerClient, rpc)
 		case "boltz":
@@ -108,11 +125,16 @@ func initServices(network string, dockerClient *docker.Client, listeners map[str
 		case "webui":
 			s = webui.New(name, resultMap, cName, dockerClient)
 		default:
-			panic(errors.New("unsupported service: " + name))
+			return nil, errors.New("unsupported service: " + name)
 		}
 
 		s.SetDisabled(disabled)
 		s.SetMode(mode)
+		if methods, ok := cfg.JsonRpcMethods[name]; ok {
+			if x, ok := s.(jsonRpcService); ok {
+				x.GetJsonRpcClient().SetAllowed(methods)
+			}
+		}
 
 		result = append(result, s)
 		resultMap[s.GetName()] = s
@@ -121,14 +143,14 @@ func initServices(network string, dockerClient *docker.Client, listeners map[str
 	}
 
 	// add self
-	s = proxy.New("proxy", resultMap, containerName(network, "proxy"), dockerClient)
+	s = proxy.New("proxy", resultMap, cfg.ContainerName("proxy"), dockerClient)
 	result = append(result, s)
 	resultMap[s.GetName()] = s
 
-	return result
+	return result, nil
 }
 
-func NewManager(network string) (*Manager, error) {
+func NewManager(cfg *config.Config) (*Manager, error) {
 	factory, err := core.NewClientFactory()
 	if err != nil {
 		return nil, err
@@ -138,16 +160,34 @@ func NewManager(network string) (*Manager, error) {
 
 	listeners := map[string]core.DockerEventListener{}
 
+	services, err := initServices(cfg, factory.GetSharedInstance(), listeners)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	syncTrackers := map[string]*core.SyncTracker{}
+	for _, s := range services {
+		if _, ok := s.(core.Syncer); ok {
+			syncTrackers[s.GetName()] = core.NewSyncTracker()
+		}
+	}
+
 	manager := Manager{
-		network:       network,
-		services:      initServices(network, factory.GetSharedInstance(), listeners),
+		config:        cfg,
+		services:      services,
 		factory:       factory,
 		logger:        logger,
 		listeners:     listeners,
-		LauncherAgent: NewLauncherAgent(network, logger.WithField("name", "LauncherAgent")),
+		LauncherAgent: NewLauncherAgent(cfg.LauncherLog, logger.WithField("name", "LauncherAgent")),
+		stopEvents:    cancel,
+		syncTrackers:  syncTrackers,
+		settingsMutex: &sync.Mutex{},
 	}
 
-	go manager.listenForDockerEvents()
+	go manager.listenForDockerEvents(ctx)
+	go manager.trackSync(ctx)
 
 	return &manager, nil
 }
@@ -201,16 +241,33 @@ type ServiceEntry struct {
 type ServiceStatus struct {
 	Service string `json:"service"`
 	Status  string `json:"status"`
+	// Sync is the sync progress of the services which sync a chain
+	Sync *core.SyncProgress `json:"sync,omitempty"`
 }
 
+// Close stops the Docker event and launcher log streams and closes every service (and its RPC connections). It
+// keeps going when a service fails to close and returns the first error.
 func (t *Manager) Close() error {
+	var result error
+
+	t.stopEvents()
+	t.LauncherAgent.Close()
+
 	for _, s := range t.services {
 		err := s.Close()
 		if err != nil {
-			return fmt.Errorf("failed to close service %s: %s", s.GetName(), err)
+			t.logger.Errorf("Failed to close service %s: %s", s.GetName(), err)
+			if result == nil {
+				result = fmt.Errorf("failed to close service %s: %s", s.GetName(), err)
+			}
 		}
 	}
-	return nil
+
+	if err := t.factory.GetSharedInstance().Close(); err != nil && result == nil {
+		result = fmt.Errorf("failed to close Docker client: %s", err)
+	}
+
+	return result
 }
 
 func (t *Manager) id2name(id string) string {
@@ -224,9 +281,9 @@ func (t *Manager) id2name(id string) string {
 	return c.Name[1:]
 }
 
-func (t *Manager) listenForDockerEvents() {
+func (t *Manager) listenForDockerEvents(ctx context.Context) {
 	client := t.factory.GetSharedInstance()
-	events, errs := client.Events(context.Background(), types.EventsOptions{})
+	events, errs := client.Events(ctx, types.EventsOptions{})
 
 	var name string
 	t.logger.Debug("Starting listening for Docker events")
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/openapi"
 	pb "github.com/opendexnetwork/opendex-docker-api/service/opendexd/opendexrpc"
 	"github.com/opendexnetwork/opendex-docker-api/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/protobuf/proto"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -134,7 +136,7 @@ func (t *Service) ConfigureRouter(r *gin.RouterGroup) {
 		defer cancel()
 		resp, err := t.ChangePassword(ctx, params.NewPassword, params.OldPassword)
 		// ignore file removal error here
-		_ = os.Remove("/root/network/.password-unset")
+		_ = os.Remove(t.passwordUnsetFile)
 		utils.HandleProtobufResponse(c, resp, err)
 	})
 
@@ -203,6 +205,84 @@ func (t *Service) ConfigureRouter(r *gin.RouterGroup) {
 		resp, err := t.RemoveOrder(ctx, params.OrderId, params.Quantity)
 		utils.HandleProtobufResponse(c, resp, err)
 	})
+
+	r.GET("/v1/opendexd/subscribeorders", func(c *gin.Context) {
+		var params SubscribeOrdersParams
+		err := c.BindQuery(&params)
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// the stream ends when the client goes away
+		stream, err := t.SubscribeOrders(c.Request.Context(), params.Existing)
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		utils.HandleProtobufStream(c, func() (proto.Message, error) {
+			return stream.Recv()
+		})
+	})
+
+	r.GET("/v1/opendexd/subscribeswaps", func(c *gin.Context) {
+		var params SubscribeSwapsParams
+		err := c.BindQuery(&params)
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		stream, err := t.SubscribeSwaps(c.Request.Context(), params.IncludeTaker)
+		if err != nil {
+			utils.JsonError(c, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		utils.HandleProtobufStream(c, func() (proto.Message, error) {
+			return stream.Recv()
+		})
+	})
+}
+
+func (t *Service) ConfigureSpec(spec *openapi.Spec) {
+	spec.Route(http.MethodGet, "/api/v1/opendexd/getinfo", "Get general information about the node").
+		Tag("opendexd").Returns(&pb.GetInfoResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/getbalance", "Get the balances of all currencies").
+		Tag("opendexd").Returns(&pb.GetBalanceResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/getbalance/:currency", "Get the balance of a currency").
+		Tag("opendexd").PathParam("currency", "e.g. BTC").Returns(&pb.GetBalanceResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/tradehistory", "List completed trades").
+		Tag("opendexd").Query("limit", uint32(0), "The maximum number of trades (0 for all)").Returns(&pb.TradeHistoryResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/tradinglimits", "Get the trading limits of all currencies").
+		Tag("opendexd").Returns(&pb.TradingLimitsResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/tradinglimits/:currency", "Get the trading limits of a currency").
+		Tag("opendexd").PathParam("currency", "e.g. BTC").Returns(&pb.TradingLimitsResponse{})
+	spec.Route(http.MethodPost, "/api/v1/opendexd/create", "Create a node and set its password").
+		Tag("opendexd").Body(CreateParams{}).Returns(&pb.CreateNodeResponse{})
+	spec.Route(http.MethodPost, "/api/v1/opendexd/restore", "Restore a node from its seed mnemonic and backups").
+		Tag("opendexd").Body(RestoreParams{}).Returns(&pb.RestoreNodeResponse{})
+	spec.Route(http.MethodPost, "/api/v1/opendexd/unlock", "Unlock the node").
+		Tag("opendexd").Body(UnlockParams{}).Returns(&pb.UnlockNodeResponse{})
+	spec.Route(http.MethodPost, "/api/v1/opendexd/changepass", "Change the password of the node").
+		Tag("opendexd").Body(ChangepasswordParams{}).Returns(&pb.ChangePasswordResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/getmnemonic", "Get the seed mnemonic of the node").
+		Tag("opendexd").Returns(&pb.GetMnemonicResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/listpairs", "List the trading pairs").
+		Tag("opendexd").Returns(&pb.ListPairsResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/listorders", "List the orders of the order book").
+		Tag("opendexd").QueryParams(ListOrdersParams{}).Returns(&pb.ListOrdersResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/orderbook", "Get the order book aggregated by price").
+		Tag("opendexd").QueryParams(OrderBookParams{}).Returns(&pb.OrderBookResponse{})
+	spec.Route(http.MethodPost, "/api/v1/opendexd/placeorder", "Place an order").
+		Tag("opendexd").Body(PlaceOrderParams{}).Returns(&pb.PlaceOrderResponse{})
+	spec.Route(http.MethodPost, "/api/v1/opendexd/removeorder", "Remove an order").
+		Tag("opendexd").Body(RemoveOrderParams{}).Returns(&pb.RemoveOrderResponse{})
+	spec.Route(http.MethodGet, "/api/v1/opendexd/subscribeorders", "Follow the order book changes").
+		Tag("opendexd").QueryParams(SubscribeOrdersParams{}).
+		Describe("Streams order additions and removals as newline delimited JSON. A failed stream ends with an {\"error\": {\"message\": ...}} line.").
+		Produces(http.StatusOK, openapi.ContentTypeNdjson, &pb.OrderUpdate{}, "A stream of order updates")
+	spec.Route(http.MethodGet, "/api/v1/opendexd/subscribeswaps", "Follow the completed swaps").
+		Tag("opendexd").QueryParams(SubscribeSwapsParams{}).
+		Describe("Streams the completed swaps as newline delimited JSON. A failed stream ends with an {\"error\": {\"message\": ...}} line.").
+		Produces(http.StatusOK, openapi.ContentTypeNdjson, &pb.SwapSuccess{}, "A stream of swaps")
 }
 
 type CreateParams struct {
@@ -220,8 +300,8 @@ type UnlockParams struct {
 }
 
 type ChangepasswordParams struct {
-	NewPassword string `json: "newPassword"`
-	OldPassword string `json: "oldPassword"`
+	NewPassword string `json:"newPassword"`
+	OldPassword string `json:"oldPassword"`
 }
 
 type ListOrdersParams struct {
@@ -238,16 +318,26 @@ type OrderBookParams struct {
 }
 
 type PlaceOrderParams struct {
-	Price             float64      `json: "price"`
-	Quantity          uint64       `json: "quantity"`
-	PairId            string       `json: "pairId"`
-	OrderId           string       `json: "orderId"`
-	Side              pb.OrderSide `json: "side"`
-	ReplaceOrderId    string       `json: "replaceOrderId"`
-	ImmediateOrCancel bool         `json: "immediateOrCancel"`
+	Price             float64      `json:"price"`
+	Quantity          uint64       `json:"quantity"`
+	PairId            string       `json:"pairId"`
+	OrderId           string       `json:"orderId"`
+	Side              pb.OrderSide `json:"side"`
+	ReplaceOrderId    string       `json:"replaceOrderId"`
+	ImmediateOrCancel bool         `json:"immediateOrCancel"`
+}
+
+type SubscribeOrdersParams struct {
+	// Existing sends the active orders first
+	Existing bool `form:"existing" json:"existing"`
+}
+
+type SubscribeSwapsParams struct {
+	// IncludeTaker includes the swaps of our own orders
+	IncludeTaker bool `form:"includeTaker" json:"includeTaker"`
 }
 
 type RemoveOrderParams struct {
-	OrderId  string `json: "orderId"`
-	Quantity uint64 `json: "quantity"`
+	OrderId  string `json:"orderId"`
+	Quantity uint64 `json:"quantity"`
 }
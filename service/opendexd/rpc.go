@@ -185,6 +185,15 @@ func (t *RpcClient) ListPairs(ctx context.Context) (*pb.ListPairsResponse, error
 	return client.ListPairs(ctx, &req)
 }
 
+func (t *RpcClient) ListCurrencies(ctx context.Context) (*pb.ListCurrenciesResponse, error) {
+	client, err := t.getClient()
+	if err != nil {
+		return nil, err
+	}
+	req := pb.ListCurrenciesRequest{}
+	return client.ListCurrencies(ctx, &req)
+}
+
 func (t *RpcClient) ListOrders(ctx context.Context, pairId string, owner pb.ListOrdersRequest_Owner, limit uint32, includeAliases bool) (*pb.ListOrdersResponse, error) {
 	client, err := t.getClient()
 	if err != nil {
@@ -240,3 +249,30 @@ func (t *RpcClient) RemoveOrder(ctx context.Context, orderId string, quantity ui
 	}
 	return client.RemoveOrder(ctx, &req)
 }
+
+func (t *RpcClient) SubscribeOrders(ctx context.Context, existing bool) (pb.Xud_SubscribeOrdersClient, error) {
+	client, err := t.getClient()
+	if err != nil {
+		return nil, err
+	}
+	req := pb.SubscribeOrdersRequest{
+		Existing: existing,
+	}
+	return client.SubscribeOrders(ctx, &req)
+}
+
+func (t *RpcClient) SubscribeSwaps(ctx context.Context, includeTaker bool) (pb.Xud_SubscribeSwapsClient, error) {
+	client, err := t.getClient()
+	if err != nil {
+		return nil, err
+	}
+	req := pb.SubscribeSwapsRequest{
+		IncludeTaker: includeTaker,
+	}
+	return client.SubscribeSwaps(ctx, &req)
+}
+
+// GrpcConns returns the connection of the service for the gRPC gateway
+func (t *RpcClient) GrpcConns() map[string]*rpc.GrpcConn {
+	return map[string]*rpc.GrpcConn{"": t.conn}
+}
@@ -7,12 +7,16 @@ import (
 	"github.com/opendexnetwork/opendex-docker-api/service/core"
 	docker "github.com/docker/docker/client"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 type Service struct {
 	*core.SingleContainerService
 	*RpcClient
+
+	dataDir           string
+	passwordUnsetFile string
 }
 
 func New(
@@ -21,12 +25,16 @@ func New(
 	containerName string,
 	dockerClient *docker.Client,
 	rpcConfig config.RpcConfig,
+	dataDir string,
+	passwordUnsetFile string,
 ) *Service {
 	base := core.NewSingleContainerService(name, services, containerName, dockerClient)
 
 	return &Service{
 		SingleContainerService: base,
 		RpcClient:              NewRpcClient(rpcConfig, base),
+		dataDir:                dataDir,
+		passwordUnsetFile:      passwordUnsetFile,
 	}
 }
 
@@ -47,7 +55,7 @@ func (t *Service) GetStatus(ctx context.Context) string {
 	resp, err := t.GetInfo(ctx)
 	if err != nil {
 		if strings.Contains(err.Error(), "opendexd is locked") {
-			if _, err := os.Stat("/root/network/data/opendexd/nodekey.dat"); os.IsNotExist(err) {
+			if _, err := os.Stat(filepath.Join(t.dataDir, "nodekey.dat")); os.IsNotExist(err) {
 				return "Wallet missing. Create with opendex-cli create/restore."
 			}
 			return "Wallet locked. Unlock with opendex-cli unlock."
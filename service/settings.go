@@ -0,0 +1,462 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/opendexnetwork/opendex-docker-api/launcher"
+	"io/ioutil"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+)
+
+const (
+	maskedSecret = "********"
+)
+
+type settingKind int
+
+const (
+	settingString settingKind = iota
+	settingPort
+	settingUrl
+)
+
+type settingField struct {
+	kind   settingKind
+	secret bool
+	// requiredBy is the mode which needs the setting
+	requiredBy string
+}
+
+type settingsSchema struct {
+	modes []string
+	// external are the settings of the external node or provider by key
+	external map[string]settingField
+	// dependents are recreated too when the mode or the external settings change
+	dependents []string
+}
+
+var (
+	fullNodeExternal = map[string]settingField{
+		"rpcHost":        {kind: settingString, requiredBy: "external"},
+		"rpcPort":        {kind: settingPort, requiredBy: "external"},
+		"rpcUser":        {kind: settingString, requiredBy: "external"},
+		"rpcPassword":    {kind: settingString, secret: true, requiredBy: "external"},
+		"zmqPubRawBlock": {kind: settingUrl, requiredBy: "external"},
+		"zmqPubRawTx":    {kind: settingUrl, requiredBy: "external"},
+	}
+
+	// settingsSchemas are the modes and external settings of the services which have them. The other services only
+	// have the disabled flag.
+	settingsSchemas = map[string]settingsSchema{
+		"bitcoind": {
+			modes:      []string{"native", "external", "light"},
+			external:   fullNodeExternal,
+			dependents: []string{"lndbtc"},
+		},
+		"litecoind": {
+			modes:      []string{"native", "external", "light"},
+			external:   fullNodeExternal,
+			dependents: []string{"lndltc"},
+		},
+		"geth": {
+			modes: []string{"native", "external", "infura", "light"},
+			external: map[string]settingField{
+				"provider":            {kind: settingUrl, requiredBy: "external"},
+				"infuraProjectId":     {kind: settingString, requiredBy: "infura"},
+				"infuraProjectSecret": {kind: settingString, secret: true},
+			},
+			dependents: []string{"connext"},
+		},
+	}
+
+	errSettingsNotFound = errors.New("service not found in config.json")
+)
+
+// SettingsError is a change rejected by the schema
+type SettingsError struct {
+	Message string
+}
+
+func (t *SettingsError) Error() string {
+	return t.Message
+}
+
+func settingsErrorf(format string, a ...interface{}) error {
+	return &SettingsError{Message: fmt.Sprintf(format, a...)}
+}
+
+type ServiceSettings struct {
+	Name     string `json:"name"`
+	Disabled bool   `json:"disabled"`
+	Mode     string `json:"mode,omitempty"`
+	// Modes are the modes the service supports
+	Modes []string `json:"modes,omitempty"`
+	// External are the settings of the external node or provider, the secrets masked
+	External map[string]interface{} `json:"external,omitempty"`
+}
+
+// SettingsChange changes the settings of a service. Missing fields are kept and a null external setting is removed.
+type SettingsChange struct {
+	Disabled *bool                  `json:"disabled"`
+	Mode     *string                `json:"mode"`
+	External map[string]interface{} `json:"external"`
+}
+
+type SettingDiff struct {
+	// Field is disabled, mode or external.<key>
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+type SettingsPreview struct {
+	Service string        `json:"service"`
+	Changes []SettingDiff `json:"changes"`
+	// Recreate are the services the launcher recreates when the change is applied
+	Recreate []string `json:"recreate"`
+}
+
+func (t *Manager) loadServicesConfig() (map[string]interface{}, error) {
+	f, err := ioutil.ReadFile(t.config.ServicesConfig)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(f, &result); err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", t.config.ServicesConfig, err)
+	}
+	if _, ok := result["services"].([]interface{}); !ok {
+		return nil, fmt.Errorf("invalid %s: no services", t.config.ServicesConfig)
+	}
+	return result, nil
+}
+
+// saveServicesConfig replaces config.json through a temporary file so the launcher never reads a partial file
+func (t *Manager) saveServicesConfig(config map[string]interface{}) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(t.config.ServicesConfig), ".config.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if info, err := os.Stat(t.config.ServicesConfig); err == nil {
+		_ = os.Chmod(f.Name(), info.Mode())
+	}
+	return os.Rename(f.Name(), t.config.ServicesConfig)
+}
+
+func findServiceEntry(config map[string]interface{}, name string) (map[string]interface{}, error) {
+	for _, item := range config["services"].([]interface{}) {
+		entry, ok := item.(map[string]interface{})
+		if ok && entry["name"] == name {
+			return entry, nil
+		}
+	}
+	return nil, errSettingsNotFound
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func maskSetting(schema settingsSchema, key string, value interface{}) interface{} {
+	if schema.external[key].secret && value != nil && value != "" {
+		return maskedSecret
+	}
+	return value
+}
+
+func settingsOf(entry map[string]interface{}) ServiceSettings {
+	name, _ := entry["name"].(string)
+	result := ServiceSettings{Name: name}
+	result.Disabled, _ = entry["disabled"].(bool)
+	result.Mode, _ = entry["mode"].(string)
+	schema, ok := settingsSchemas[name]
+	if !ok {
+		return result
+	}
+	result.Modes = schema.modes
+	result.External = map[string]interface{}{}
+	if external, ok := entry["external"].(map[string]interface{}); ok {
+		for key, value := range external {
+			result.External[key] = maskSetting(schema, key, value)
+		}
+	}
+	return result
+}
+
+// GetSettings returns the settings of the services in config.json
+func (t *Manager) GetSettings() ([]ServiceSettings, error) {
+	config, err := t.loadServicesConfig()
+	if err != nil {
+		return nil, err
+	}
+	result := []ServiceSettings{}
+	for _, item := range config["services"].([]interface{}) {
+		if entry, ok := item.(map[string]interface{}); ok {
+			result = append(result, settingsOf(entry))
+		}
+	}
+	return result, nil
+}
+
+func (t *Manager) GetServiceSettings(name string) (*ServiceSettings, error) {
+	config, err := t.loadServicesConfig()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := findServiceEntry(config, name)
+	if err != nil {
+		return nil, err
+	}
+	result := settingsOf(entry)
+	return &result, nil
+}
+
+func validateSetting(key string, field settingField, value interface{}) error {
+	switch field.kind {
+	case settingPort:
+		port, ok := value.(float64)
+		if !ok || port != math.Trunc(port) || port < 1 || port > 65535 {
+			return settingsErrorf("external.%s must be a port number", key)
+		}
+	case settingUrl:
+		s, ok := value.(string)
+		if !ok {
+			return settingsErrorf("external.%s must be a string", key)
+		}
+		if s == "" {
+			return nil
+		}
+		u, err := url.Parse(s)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return settingsErrorf("external.%s must be a URL like tcp://host:port or https://host", key)
+		}
+	default:
+		if _, ok := value.(string); !ok {
+			return settingsErrorf("external.%s must be a string", key)
+		}
+	}
+	return nil
+}
+
+// validateSettings checks the settings of a service entry against its schema
+func validateSettings(entry map[string]interface{}) error {
+	name, _ := entry["name"].(string)
+	schema, ok := settingsSchemas[name]
+	if !ok {
+		if entry["mode"] != nil && entry["mode"] != "" {
+			return settingsErrorf("%s has no modes", name)
+		}
+		if entry["external"] != nil {
+			return settingsErrorf("%s has no external settings", name)
+		}
+		return nil
+	}
+
+	mode, _ := entry["mode"].(string)
+	if mode != "" {
+		found := false
+		for _, m := range schema.modes {
+			if m == mode {
+				found = true
+			}
+		}
+		if !found {
+			return settingsErrorf("invalid mode of %s: %s (expected one of %v)", name, mode, schema.modes)
+		}
+	}
+
+	external, _ := entry["external"].(map[string]interface{})
+	for _, key := range sortedKeys(external) {
+		field, ok := schema.external[key]
+		if !ok {
+			return settingsErrorf("unknown external setting of %s: %s", name, key)
+		}
+		if err := validateSetting(key, field, external[key]); err != nil {
+			return err
+		}
+	}
+	keys := make([]string, 0, len(schema.external))
+	for key := range schema.external {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if field := schema.external[key]; field.requiredBy != "" && field.requiredBy == mode {
+			if value, ok := external[key]; !ok || value == "" {
+				return settingsErrorf("the %s mode of %s requires external.%s", mode, name, key)
+			}
+		}
+	}
+	return nil
+}
+
+// applyChange returns the entry with the change and the differences
+func applyChange(entry map[string]interface{}, change SettingsChange) (map[string]interface{}, []SettingDiff, error) {
+	name, _ := entry["name"].(string)
+	schema := settingsSchemas[name]
+
+	result := map[string]interface{}{}
+	for key, value := range entry {
+		result[key] = value
+	}
+	diffs := []SettingDiff{}
+
+	if change.Disabled != nil {
+		old, _ := entry["disabled"].(bool)
+		if old != *change.Disabled {
+			diffs = append(diffs, SettingDiff{Field: "disabled", Old: old, New: *change.Disabled})
+		}
+		result["disabled"] = *change.Disabled
+	}
+
+	if change.Mode != nil {
+		old, _ := entry["mode"].(string)
+		if old != *change.Mode {
+			diffs = append(diffs, SettingDiff{Field: "mode", Old: old, New: *change.Mode})
+		}
+		result["mode"] = *change.Mode
+	}
+
+	if change.External != nil {
+		old, _ := entry["external"].(map[string]interface{})
+		external := map[string]interface{}{}
+		for key, value := range old {
+			external[key] = value
+		}
+		for _, key := range sortedKeys(change.External) {
+			value := change.External[key]
+			if schema.external[key].secret && value == maskedSecret {
+				// the masked value of GetSettings sent back unchanged
+				continue
+			}
+			if value == nil {
+				delete(external, key)
+			} else {
+				external[key] = value
+			}
+			if !reflect.DeepEqual(old[key], value) {
+				diffs = append(diffs, SettingDiff{
+					Field: "external." + key,
+					Old:   maskSetting(schema, key, old[key]),
+					New:   maskSetting(schema, key, value),
+				})
+			}
+		}
+		result["external"] = external
+	}
+
+	if err := validateSettings(result); err != nil {
+		return nil, nil, err
+	}
+	return result, diffs, nil
+}
+
+// recreatedBy returns the services to recreate for the differences
+func recreatedBy(name string, diffs []SettingDiff) []string {
+	if len(diffs) == 0 {
+		return []string{}
+	}
+	result := []string{name}
+	for _, d := range diffs {
+		if d.Field != "disabled" {
+			return append(result, settingsSchemas[name].dependents...)
+		}
+	}
+	return result
+}
+
+func (t *Manager) previewSettings(name string, change SettingsChange) (map[string]interface{}, *SettingsPreview, error) {
+	config, err := t.loadServicesConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	entry, err := findServiceEntry(config, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated, diffs, err := applyChange(entry, change)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, item := range config["services"].([]interface{}) {
+		if e, ok := item.(map[string]interface{}); ok && e["name"] == name {
+			config["services"].([]interface{})[i] = updated
+		}
+	}
+	return config, &SettingsPreview{Service: name, Changes: diffs, Recreate: recreatedBy(name, diffs)}, nil
+}
+
+// PreviewSettings validates a change of the settings of a service and returns the differences without applying it
+func (t *Manager) PreviewSettings(name string, change SettingsChange) (*SettingsPreview, error) {
+	_, preview, err := t.previewSettings(name, change)
+	return preview, err
+}
+
+// writeSettings validates the change and writes it to config.json
+func (t *Manager) writeSettings(name string, change SettingsChange) (*SettingsPreview, error) {
+	t.settingsMutex.Lock()
+	defer t.settingsMutex.Unlock()
+
+	config, preview, err := t.previewSettings(name, change)
+	if err != nil {
+		return nil, err
+	}
+	if len(preview.Changes) == 0 {
+		return preview, nil
+	}
+	if err := t.saveServicesConfig(config); err != nil {
+		return nil, err
+	}
+	t.logger.Infof("Updated the settings of %s in %s: %v", name, t.config.ServicesConfig, preview.Changes)
+
+	if s, err := t.GetService(name); err == nil {
+		settings, _ := t.GetServiceSettings(name)
+		if settings != nil {
+			s.SetDisabled(settings.Disabled)
+			s.SetMode(settings.Mode)
+		}
+	}
+
+	return preview, nil
+}
+
+// ApplySettings writes a change of the settings of a service to config.json and asks the launcher to recreate the
+// affected containers, waiting for the launcher until ctx is done. The returned preview is set when config.json was
+// written even if the launcher failed.
+func (t *Manager) ApplySettings(ctx context.Context, name string, change SettingsChange) (*SettingsPreview, error) {
+	preview, err := t.writeSettings(name, change)
+	if err != nil || len(preview.Changes) == 0 {
+		return preview, err
+	}
+
+	// the recreation can take minutes so other changes aren't blocked meanwhile; the launcher reads the latest
+	// config.json anyway
+	if err := launcher.Recreate(ctx, preview.Recreate); err != nil {
+		return preview, fmt.Errorf("config.json was updated but the launcher failed to recreate %v: %s", preview.Recreate, err)
+	}
+	return preview, nil
+}
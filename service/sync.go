@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"github.com/opendexnetwork/opendex-docker-api/config"
+	"github.com/opendexnetwork/opendex-docker-api/service/core"
+	"time"
+)
+
+var (
+	// SyncInterval is how often the heights of the syncing services are sampled
+	SyncInterval = 15 * time.Second
+)
+
+type ServiceSync struct {
+	Service string `json:"service"`
+	core.SyncProgress
+}
+
+func (t *Manager) sampleSync() {
+	for _, svc := range t.services {
+		s, ok := svc.(core.Syncer)
+		if !ok || svc.IsDisabled() {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), config.DefaultApiTimeout)
+		current, total, err := s.GetSyncHeights(ctx)
+		cancel()
+		if err != nil {
+			if err == core.ErrNotSyncing {
+				// e.g. switched to light mode; a stale progress would be reported forever otherwise
+				t.syncTrackers[svc.GetName()].Reset()
+			} else {
+				t.logger.Debugf("Failed to get the sync heights of %s: %s", svc.GetName(), err)
+			}
+			continue
+		}
+		t.syncTrackers[svc.GetName()].Sample(current, total, time.Now())
+	}
+}
+
+// trackSync samples the heights of the syncing services every SyncInterval until ctx is done
+func (t *Manager) trackSync(ctx context.Context) {
+	ticker := time.NewTicker(SyncInterval)
+	defer ticker.Stop()
+	for {
+		t.sampleSync()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// GetSyncProgress returns the sync progress of a service or nil if it isn't syncing a chain or has no sample yet
+func (t *Manager) GetSyncProgress(service string) *core.SyncProgress {
+	tracker, ok := t.syncTrackers[service]
+	if !ok {
+		return nil
+	}
+	return tracker.Progress()
+}
+
+// GetSync returns the sync progress of the services which sync a chain
+func (t *Manager) GetSync() []ServiceSync {
+	result := []ServiceSync{}
+	for _, svc := range t.services {
+		if p := t.GetSyncProgress(svc.GetName()); p != nil {
+			result = append(result, ServiceSync{Service: svc.GetName(), SyncProgress: *p})
+		}
+	}
+	return result
+}
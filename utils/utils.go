@@ -1,14 +1,23 @@
 package utils
 
 import (
+	"encoding/json"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
+	"io"
 	"net/http"
 	"os"
 )
 
+// JsonError writes the error response and attaches the error to the context for the middlewares
 func JsonError(c *gin.Context, message string, code int) {
+	_ = c.Error(errors.New(message))
+	writeJsonError(c, message, code)
+}
+
+func writeJsonError(c *gin.Context, message string, code int) {
 	c.Header("Content-Type", "application/json; charset=utf-8")
 	c.Header("X-Content-Type-Options", "nosniff")
 	c.JSON(code, gin.H{
@@ -18,7 +27,9 @@ func JsonError(c *gin.Context, message string, code int) {
 
 func HandleProtobufResponse(c *gin.Context, resp proto.Message, err error) {
 	if err != nil {
-		JsonError(c, err.Error(), http.StatusInternalServerError)
+		// keep the original (gRPC) error so that middlewares can inspect its status
+		_ = c.Error(err)
+		writeJsonError(c, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	m := jsonpb.Marshaler{EmitDefaults: true}
@@ -30,6 +41,45 @@ func HandleProtobufResponse(c *gin.Context, resp proto.Message, err error) {
 	c.Header("Content-Type", "application/json; charset=utf-8")
 }
 
+// StreamError is the last line of a newline delimited JSON stream which failed after the response started
+type StreamError struct {
+	Error ErrorMessage `json:"error"`
+}
+
+type ErrorMessage struct {
+	Message string `json:"message"`
+}
+
+// HandleProtobufStream writes the messages returned by recv as newline delimited JSON until recv returns io.EOF or
+// the client goes away. Other errors are written as a final StreamError line.
+func HandleProtobufStream(c *gin.Context, recv func() (proto.Message, error)) {
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	m := jsonpb.Marshaler{EmitDefaults: true}
+	for {
+		msg, err := recv()
+		if err != nil {
+			if err != io.EOF && c.Request.Context().Err() == nil {
+				_ = c.Error(err)
+				line, _ := json.Marshal(StreamError{Error: ErrorMessage{Message: err.Error()}})
+				_, _ = c.Writer.Write(append(line, '\n'))
+			}
+			return
+		}
+		if err := m.Marshal(c.Writer, msg); err != nil {
+			_ = c.Error(err)
+			return
+		}
+		if _, err := c.Writer.WriteString("\n"); err != nil {
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
 func FileExists(path string) bool {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return false